@@ -12,7 +12,6 @@ import (
 	"github.com/docker/containerd/api/grpc/types"
 	"github.com/docker/containerd/runtime"
 	"github.com/docker/containerd/supervisor"
-	"github.com/opencontainers/runc/libcontainer"
 	"github.com/opencontainers/runc/libcontainer/cgroups"
 	"github.com/opencontainers/specs"
 	"golang.org/x/net/context"
@@ -102,8 +101,11 @@ func (s *apiServer) AddProcess(ctx context.Context, r *types.AddProcessRequest)
 	if err := <-e.Err; err != nil {
 		return nil, err
 	}
-	<-e.StartResponse
-	return &types.AddProcessResponse{}, nil
+	sr := <-e.StartResponse
+	if sr.Err != nil {
+		return nil, sr.Err
+	}
+	return &types.AddProcessResponse{SystemPid: uint32(sr.Process.SystemPid())}, nil
 }
 
 func (s *apiServer) CreateCheckpoint(ctx context.Context, r *types.CreateCheckpointRequest) (*types.CreateCheckpointResponse, error) {
@@ -116,7 +118,7 @@ func (s *apiServer) CreateCheckpoint(ctx context.Context, r *types.CreateCheckpo
 		UnixSockets: r.Checkpoint.UnixSockets,
 		Shell:       r.Checkpoint.Shell,
 	}
-	s.sv.SendTask(e)
+	s.sv.SendTaskContext(ctx, e)
 	if err := <-e.Err; err != nil {
 		return nil, err
 	}
@@ -140,29 +142,20 @@ func (s *apiServer) DeleteCheckpoint(ctx context.Context, r *types.DeleteCheckpo
 }
 
 func (s *apiServer) ListCheckpoint(ctx context.Context, r *types.ListCheckpointRequest) (*types.ListCheckpointResponse, error) {
-	e := supervisor.NewTask(supervisor.GetContainerTaskType)
+	e := supervisor.NewTask(supervisor.ListCheckpointsTaskType)
+	e.ID = r.Id
 	s.sv.SendTask(e)
 	if err := <-e.Err; err != nil {
-		return nil, err
-	}
-	var container runtime.Container
-	for _, c := range e.Containers {
-		if c.ID() == r.Id {
-			container = c
-			break
+		if err == supervisor.ErrContainerNotFound {
+			return nil, grpc.Errorf(codes.NotFound, "no such containers")
 		}
-	}
-	if container == nil {
-		return nil, grpc.Errorf(codes.NotFound, "no such containers")
-	}
-	var out []*types.Checkpoint
-	checkpoints, err := container.Checkpoints()
-	if err != nil {
 		return nil, err
 	}
-	for _, c := range checkpoints {
+	var out []*types.Checkpoint
+	for _, c := range e.Checkpoints {
 		out = append(out, &types.Checkpoint{
 			Name:        c.Name,
+			Exit:        c.Exit,
 			Tcp:         c.Tcp,
 			Shell:       c.Shell,
 			UnixSockets: c.UnixSockets,
@@ -277,7 +270,10 @@ func (s *apiServer) Events(r *types.EventsRequest, stream types.API_EventsServer
 	if r.Timestamp != 0 {
 		t = time.Unix(int64(r.Timestamp), 0)
 	}
-	events := s.sv.Events(t)
+	events, err := s.sv.Events(t)
+	if err != nil {
+		return err
+	}
 	defer s.sv.Unsubscribe(events)
 	for e := range events {
 		if err := stream.Send(&types.Event{
@@ -297,7 +293,7 @@ func (s *apiServer) Stats(ctx context.Context, r *types.StatsRequest) (*types.St
 	e := supervisor.NewTask(supervisor.StatsTaskType)
 	e.ID = r.Id
 	e.Stat = make(chan *runtime.Stat, 1)
-	s.sv.SendTask(e)
+	s.sv.SendTaskContext(ctx, e)
 	if err := <-e.Err; err != nil {
 		return nil, err
 	}
@@ -311,8 +307,8 @@ func convertToPb(st *runtime.Stat) *types.StatsResponse {
 		Timestamp:   uint64(st.Timestamp.Unix()),
 		CgroupStats: &types.CgroupStats{},
 	}
-	lcSt, ok := st.Data.(*libcontainer.Stats)
-	if !ok {
+	lcSt := st.Raw
+	if lcSt == nil || lcSt.CgroupStats == nil {
 		return pbSt
 	}
 	cpuSt := lcSt.CgroupStats.CpuStats
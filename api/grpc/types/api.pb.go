@@ -172,6 +172,7 @@ func (*User) ProtoMessage()               {}
 func (*User) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
 
 type AddProcessResponse struct {
+	SystemPid uint32 `protobuf:"varint,1,opt,name=systemPid" json:"systemPid,omitempty"`
 }
 
 func (m *AddProcessResponse) Reset()                    { *m = AddProcessResponse{} }
@@ -1093,100 +1094,100 @@ var _API_serviceDesc = grpc.ServiceDesc{
 }
 
 var fileDescriptor0 = []byte{
-	// 1517 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xb4, 0x58, 0xd9, 0x6e, 0x1c, 0x45,
-	0x17, 0xf6, 0xec, 0x33, 0x67, 0x16, 0xdb, 0xed, 0x6d, 0x3c, 0xf9, 0xf3, 0xc7, 0x74, 0x02, 0x89,
-	0x50, 0x64, 0x05, 0x87, 0x25, 0x04, 0x09, 0x08, 0x4e, 0x44, 0x40, 0x09, 0x98, 0xd8, 0x46, 0xe2,
-	0x86, 0x51, 0x4f, 0x77, 0x31, 0xd3, 0xb8, 0x37, 0xba, 0xaa, 0xbd, 0x48, 0x3c, 0x01, 0xdc, 0xf3,
-	0x16, 0x48, 0x5c, 0xf1, 0x00, 0x3c, 0x0e, 0x4f, 0xc1, 0xe9, 0xda, 0xa6, 0xbb, 0x67, 0x31, 0xb9,
-	0xe0, 0xc6, 0x72, 0x55, 0x9d, 0xf3, 0x9d, 0xef, 0x6c, 0x55, 0x7d, 0x06, 0x5a, 0x56, 0xe4, 0xee,
-	0x47, 0x71, 0xc8, 0x42, 0xa3, 0xc6, 0xae, 0x22, 0x42, 0xcd, 0x11, 0x6c, 0x9e, 0x46, 0x8e, 0xc5,
-	0xc8, 0x51, 0x1c, 0xda, 0x84, 0xd2, 0x57, 0xe4, 0xa7, 0x84, 0x50, 0x66, 0x00, 0x94, 0x5d, 0xa7,
-	0x5f, 0xda, 0x2b, 0xdd, 0x6b, 0x19, 0x6d, 0xa8, 0x44, 0xb8, 0x28, 0xf3, 0x05, 0x9e, 0xd8, 0x5e,
-	0x48, 0xc9, 0x31, 0x73, 0xdc, 0xa0, 0x5f, 0xc1, 0xbd, 0xa6, 0xd1, 0x85, 0xda, 0x85, 0xeb, 0xb0,
-	0x49, 0xbf, 0x8a, 0xcb, 0xae, 0xd1, 0x83, 0xfa, 0x84, 0xb8, 0xe3, 0x09, 0xeb, 0xd7, 0xd2, 0xb5,
-	0xb9, 0x03, 0x5b, 0x05, 0x1b, 0x34, 0x0a, 0x03, 0x4a, 0xcc, 0x5f, 0x4b, 0xb0, 0x7d, 0x18, 0x13,
-	0x3c, 0x39, 0x0c, 0x03, 0x66, 0xb9, 0x01, 0x89, 0xe7, 0xd9, 0xc7, 0xc5, 0x28, 0x09, 0x1c, 0x8f,
-	0x1c, 0x59, 0x68, 0x63, 0x4a, 0x63, 0x42, 0xec, 0xb3, 0x28, 0x74, 0x03, 0xc6, 0x69, 0xb4, 0x52,
-	0x1a, 0x94, 0xb3, 0xaa, 0xf2, 0x25, 0xd2, 0xc0, 0x65, 0x98, 0x08, 0x1a, 0x6a, 0x4d, 0xe2, 0xb8,
-	0x5f, 0x57, 0x6b, 0xcf, 0x1a, 0x11, 0x8f, 0xf6, 0x1b, 0x7b, 0x95, 0x7b, 0x2d, 0xf3, 0x63, 0xd8,
-	0x99, 0x21, 0x23, 0x88, 0x1a, 0xb7, 0xa1, 0x65, 0xab, 0x4d, 0x4e, 0xaa, 0x7d, 0xb0, 0xb6, 0xcf,
-	0x03, 0xb8, 0xaf, 0x85, 0xcd, 0x47, 0xd0, 0x3d, 0x76, 0xc7, 0x81, 0xe5, 0x5d, 0x1b, 0xc3, 0x94,
-	0x09, 0x97, 0xe4, 0xc4, 0xbb, 0xe6, 0x1a, 0xf4, 0x94, 0xa6, 0x8c, 0xcc, 0x1f, 0x25, 0x58, 0x7f,
-	0xe2, 0x38, 0x4b, 0x92, 0xb2, 0x06, 0x4d, 0x46, 0x62, 0xdf, 0x4d, 0x51, 0xca, 0x3c, 0x0b, 0xbb,
-	0x50, 0x4d, 0x28, 0xf2, 0xab, 0x70, 0x7e, 0x6d, 0xc9, 0xef, 0x14, 0xb7, 0x8c, 0x0e, 0x54, 0xad,
-	0x78, 0x4c, 0x31, 0x30, 0x15, 0xc1, 0x85, 0x04, 0xe7, 0x18, 0x15, 0xb9, 0xb0, 0x2f, 0x1c, 0x19,
-	0x12, 0xc9, 0xb2, 0x91, 0x0f, 0x67, 0xb3, 0x10, 0xce, 0x56, 0x21, 0x9c, 0x90, 0xae, 0xd1, 0xfd,
-	0x2a, 0xb7, 0x85, 0x18, 0x89, 0x64, 0xd9, 0x4d, 0x17, 0x63, 0xe9, 0x76, 0xd7, 0xd8, 0x86, 0x9e,
-	0xe5, 0x38, 0x2e, 0x73, 0x43, 0x24, 0xfd, 0xb9, 0xeb, 0x50, 0xa4, 0x5a, 0x41, 0xf7, 0x37, 0xc1,
-	0xc8, 0xfa, 0x2a, 0x43, 0xf0, 0x42, 0xa7, 0x43, 0xe7, 0x79, 0x5e, 0x1c, 0xde, 0xcc, 0x15, 0x42,
-	0x99, 0xfb, 0xbe, 0xae, 0x72, 0xa3, 0x0f, 0xcc, 0x01, 0xf4, 0x67, 0xd1, 0xa4, 0xa5, 0x87, 0xb0,
-	0xf3, 0x94, 0x78, 0xe4, 0x3a, 0x4b, 0x18, 0xc4, 0xc0, 0xf2, 0x89, 0xc8, 0x61, 0x0a, 0x38, 0xab,
-	0x24, 0x01, 0x6f, 0xc3, 0xd6, 0x0b, 0x97, 0xb2, 0xa5, 0x70, 0xe6, 0x77, 0x00, 0x53, 0x01, 0x0d,
-	0xae, 0x4d, 0x91, 0x4b, 0x97, 0xc9, 0xc4, 0x62, 0x10, 0x99, 0x1d, 0xc9, 0x5e, 0xdb, 0x80, 0x76,
-	0x12, 0xb8, 0x97, 0xc7, 0xa1, 0x7d, 0x46, 0x18, 0xe5, 0xa5, 0xce, 0x1b, 0x90, 0x4e, 0x88, 0xe7,
-	0xf1, 0x4a, 0x6f, 0x9a, 0x9f, 0xc2, 0x76, 0xd1, 0xbe, 0x2c, 0xe4, 0xb7, 0xa0, 0x3d, 0x8d, 0x16,
-	0x45, 0x6b, 0x95, 0x45, 0xe1, 0xea, 0x1c, 0x33, 0x8c, 0xd6, 0x3c, 0xe2, 0x7b, 0xd0, 0xd3, 0x45,
-	0xcf, 0x85, 0x44, 0x29, 0x58, 0x2c, 0xa1, 0x52, 0xe2, 0xf7, 0x12, 0x34, 0x64, 0x3a, 0x55, 0x49,
-	0xfd, 0x87, 0x45, 0xbb, 0x0e, 0x2d, 0x7a, 0x45, 0x19, 0xf1, 0x8f, 0x64, 0xe9, 0x76, 0x5f, 0xb7,
-	0x74, 0x7f, 0x86, 0x96, 0xf6, 0xe8, 0xda, 0x9b, 0xe7, 0x0d, 0x68, 0x45, 0xc2, 0x37, 0x22, 0x0a,
-	0xb8, 0x7d, 0xd0, 0x93, 0xb4, 0x95, 0xcf, 0xd3, 0x78, 0x54, 0x0b, 0x37, 0x8d, 0xa0, 0x8f, 0x9e,
-	0x45, 0x69, 0xf9, 0xd7, 0x79, 0xf9, 0xdf, 0x85, 0xc6, 0x4b, 0xcb, 0x9e, 0xa0, 0xf1, 0xf4, 0xc0,
-	0x8e, 0x64, 0x18, 0xf9, 0x3d, 0xea, 0x13, 0x3f, 0x8c, 0xaf, 0xb8, 0xe5, 0xaa, 0xf9, 0x2d, 0x5e,
-	0x30, 0x22, 0x29, 0x32, 0x9b, 0x77, 0xb0, 0xf6, 0x15, 0x6f, 0x95, 0xcc, 0x99, 0x7b, 0xc9, 0xb8,
-	0x05, 0x0d, 0x5f, 0xe0, 0xcb, 0xf6, 0x50, 0x74, 0xa5, 0x55, 0xf3, 0x09, 0x6c, 0x8b, 0xfb, 0x79,
-	0xe9, 0x2d, 0x3c, 0x73, 0x83, 0x09, 0x0f, 0xf9, 0xd5, 0x6b, 0xee, 0xc2, 0xce, 0x0c, 0x84, 0x6c,
-	0x06, 0x13, 0xba, 0xcf, 0xce, 0x09, 0x56, 0x9b, 0x02, 0xc5, 0x7c, 0x31, 0xd7, 0xc7, 0xff, 0x2c,
-	0x3f, 0xe2, 0xd8, 0x55, 0xf3, 0x1b, 0xa8, 0x71, 0x99, 0x34, 0x00, 0x29, 0x37, 0x69, 0x52, 0x98,
-	0x9f, 0x67, 0xb1, 0xab, 0xe8, 0x54, 0x55, 0x09, 0x4c, 0x21, 0x6b, 0x1c, 0xf2, 0xcf, 0x12, 0x74,
-	0xbe, 0x22, 0xec, 0x22, 0x8c, 0xcf, 0xd2, 0xa0, 0xd1, 0x42, 0x87, 0x61, 0x25, 0xc6, 0x97, 0xc3,
-	0xd1, 0x15, 0xc3, 0x24, 0xf2, 0xe8, 0xa6, 0xb9, 0xc6, 0x9d, 0x23, 0x4b, 0xf4, 0x55, 0x85, 0xef,
-	0x21, 0xee, 0xab, 0xcb, 0x21, 0x16, 0x4a, 0x18, 0x8b, 0x5c, 0x72, 0x31, 0xdc, 0x72, 0xe2, 0x30,
-	0x8a, 0x88, 0x23, 0x6c, 0xa5, 0x60, 0x27, 0x0a, 0xac, 0xae, 0xa4, 0x70, 0x27, 0x92, 0x60, 0x0d,
-	0x05, 0x76, 0xa2, 0xc1, 0x9a, 0x19, 0x31, 0x05, 0xd6, 0xe2, 0xc4, 0x7d, 0x68, 0x1e, 0x46, 0xc9,
-	0x29, 0xb5, 0xc6, 0x24, 0x6d, 0x76, 0x16, 0x32, 0xcb, 0x1b, 0x26, 0xe9, 0x52, 0x04, 0xcb, 0xd8,
-	0x84, 0x4e, 0x44, 0x62, 0xac, 0x13, 0xb9, 0x5b, 0xc6, 0xbc, 0x57, 0x8d, 0x1b, 0xb0, 0xc1, 0x97,
-	0x43, 0x37, 0x18, 0x9e, 0x91, 0x38, 0x20, 0x9e, 0x1f, 0x3a, 0x44, 0xfa, 0xb1, 0x0b, 0xeb, 0xfa,
-	0x30, 0x6d, 0x37, 0x7e, 0xc4, 0xfd, 0x31, 0x4f, 0xa0, 0x77, 0x32, 0xc1, 0x2f, 0x02, 0xe6, 0xb9,
-	0xc1, 0xf8, 0xa9, 0xc5, 0x2c, 0x63, 0x15, 0x1a, 0x88, 0xef, 0x86, 0x0e, 0x95, 0x06, 0x51, 0x9b,
-	0x09, 0x11, 0xe2, 0x0c, 0xd5, 0x91, 0x08, 0x1a, 0x5e, 0xe9, 0xd3, 0xa3, 0x34, 0x05, 0xc2, 0xa0,
-	0xf9, 0x3d, 0x77, 0x42, 0x04, 0xde, 0xc4, 0xc7, 0x53, 0x93, 0x15, 0x8f, 0xe7, 0xaa, 0x2a, 0x52,
-	0xe5, 0xe8, 0x3e, 0xac, 0x32, 0xcd, 0x62, 0x88, 0x85, 0x64, 0xc9, 0x5a, 0xdd, 0x92, 0x92, 0x79,
-	0x8e, 0xe6, 0x27, 0x00, 0x2f, 0x79, 0x6b, 0x70, 0xc6, 0xd8, 0xee, 0xd9, 0x00, 0x61, 0xa0, 0x7d,
-	0xeb, 0x52, 0x47, 0x27, 0xdd, 0x42, 0x9f, 0x7e, 0xb0, 0x5c, 0xcf, 0x96, 0xdf, 0x0a, 0x55, 0xf3,
-	0xef, 0x12, 0xb4, 0x05, 0x82, 0x20, 0x89, 0x10, 0x36, 0xb6, 0x83, 0x82, 0xd8, 0x53, 0x88, 0xf9,
-	0x07, 0x25, 0x63, 0x13, 0xdf, 0x1d, 0x7a, 0x61, 0x45, 0xd2, 0x4a, 0x65, 0x91, 0xd8, 0x5d, 0xe8,
-	0x88, 0x6c, 0x48, 0xc1, 0xea, 0x22, 0xc1, 0xfb, 0xe9, 0x95, 0x85, 0x4c, 0xf8, 0x15, 0xd1, 0x3e,
-	0xb8, 0x99, 0x93, 0xe0, 0x1c, 0xf7, 0xf9, 0xdf, 0x67, 0x01, 0x8b, 0xaf, 0x06, 0xf7, 0x01, 0xa6,
-	0xab, 0xb4, 0x17, 0xce, 0xc8, 0x95, 0xac, 0x6c, 0xf4, 0xe4, 0xdc, 0xf2, 0x12, 0xe9, 0xf9, 0xe3,
-	0xf2, 0xa3, 0x92, 0xf9, 0x25, 0xac, 0x7e, 0xe6, 0x9d, 0xb9, 0x61, 0x46, 0x05, 0xa5, 0x7c, 0xeb,
-	0xc7, 0x30, 0x96, 0xfe, 0xa6, 0x4b, 0x37, 0xc0, 0xa5, 0x08, 0x17, 0x36, 0x5e, 0x18, 0x4d, 0xbf,
-	0xaa, 0x04, 0x9e, 0xa8, 0x97, 0xbf, 0x2a, 0x00, 0x53, 0x30, 0xe3, 0x31, 0x0c, 0xdc, 0x70, 0x88,
-	0x25, 0x75, 0xee, 0xda, 0x44, 0xb4, 0xc0, 0x30, 0x26, 0x76, 0x12, 0x53, 0xf7, 0x9c, 0xc8, 0x2b,
-	0x69, 0x5b, 0xfa, 0x52, 0xe4, 0xf0, 0x1e, 0x6c, 0x4d, 0x75, 0x9d, 0x8c, 0x5a, 0x79, 0xa9, 0xda,
-	0x43, 0xd8, 0x40, 0x35, 0xbc, 0x4b, 0x92, 0x9c, 0x52, 0x65, 0xa9, 0xd2, 0x87, 0xb0, 0x9b, 0xe1,
-	0x99, 0x56, 0x6a, 0x46, 0xb5, 0xba, 0x54, 0xf5, 0x7d, 0xd8, 0x46, 0xd5, 0x0b, 0xcb, 0x65, 0x45,
-	0xbd, 0xda, 0xbf, 0xe0, 0xe9, 0x93, 0x78, 0x9c, 0xe3, 0x59, 0x5f, 0xaa, 0xf4, 0x0e, 0xac, 0xa3,
-	0x52, 0xc1, 0x4e, 0xe3, 0x3a, 0x15, 0x4a, 0x6c, 0x86, 0xb7, 0x4a, 0x46, 0xa5, 0xb9, 0x4c, 0x05,
-	0x6f, 0xfc, 0xce, 0xf3, 0x64, 0x4c, 0x98, 0x37, 0xd2, 0xd5, 0xff, 0xba, 0x0d, 0xf4, 0x4b, 0x19,
-	0xda, 0x87, 0xe3, 0x38, 0x4c, 0xa2, 0x5c, 0x97, 0x8b, 0x1a, 0x9e, 0xe9, 0x72, 0x21, 0x73, 0x0f,
-	0x3a, 0xe2, 0x41, 0x93, 0x62, 0xa2, 0xb9, 0x8c, 0xd9, 0x52, 0x4f, 0xbf, 0x53, 0x46, 0x29, 0x67,
-	0x29, 0x98, 0x6f, 0xaf, 0x4c, 0xf9, 0x7d, 0x04, 0xdd, 0x89, 0x70, 0x44, 0x4a, 0x8a, 0x54, 0xde,
-	0x51, 0x96, 0xa7, 0x04, 0xf7, 0xb3, 0x0e, 0x8b, 0x26, 0x7a, 0x0e, 0xeb, 0x33, 0x9b, 0xf9, 0x5e,
-	0x32, 0xb3, 0xbd, 0xd4, 0x3e, 0xd8, 0x90, 0xb0, 0x59, 0x2d, 0xde, 0x60, 0x97, 0xe2, 0x65, 0xd6,
-	0x1f, 0xaf, 0xc6, 0xdb, 0xd0, 0x0d, 0xc4, 0xe3, 0xa3, 0x23, 0x52, 0xc9, 0x00, 0xe4, 0x1e, 0x26,
-	0x8c, 0x8a, 0xcd, 0x79, 0xce, 0x8d, 0x4a, 0x36, 0xc6, 0xb9, 0x67, 0x4e, 0xa4, 0x41, 0x7e, 0xa8,
-	0xcd, 0x1b, 0x11, 0x0e, 0x7e, 0xab, 0x43, 0xe5, 0xc9, 0xd1, 0x17, 0xc6, 0x2b, 0x58, 0x2d, 0x0c,
-	0x36, 0x86, 0xba, 0x5e, 0xe6, 0x4f, 0x5f, 0x83, 0xff, 0x2f, 0x3a, 0x96, 0x6f, 0xfa, 0x4a, 0x8a,
-	0x59, 0x78, 0xf0, 0x35, 0xe6, 0xfc, 0x6f, 0x09, 0x8d, 0xb9, 0xe8, 0x3b, 0x61, 0xc5, 0xf8, 0x00,
-	0xea, 0x62, 0x0c, 0x32, 0x36, 0xa5, 0x6c, 0x6e, 0x9e, 0x1a, 0x6c, 0x15, 0x76, 0xb5, 0xe2, 0x0b,
-	0xe8, 0xe6, 0x06, 0x4c, 0xe3, 0x46, 0xce, 0x56, 0x7e, 0x8a, 0x1a, 0xfc, 0x6f, 0xfe, 0xa1, 0x46,
-	0x3b, 0x04, 0x98, 0x8e, 0x23, 0x46, 0x5f, 0x4a, 0xcf, 0x4c, 0x63, 0x83, 0xdd, 0x39, 0x27, 0x1a,
-	0xe4, 0x14, 0xd6, 0x8a, 0xf3, 0x86, 0x51, 0x88, 0x6a, 0x71, 0x3a, 0x18, 0xdc, 0x5a, 0x78, 0x9e,
-	0x85, 0x2d, 0x4e, 0x1d, 0x1a, 0x76, 0xc1, 0x0c, 0xa3, 0x61, 0x17, 0x8e, 0x2b, 0x2b, 0xc6, 0xd7,
-	0xd0, 0xcb, 0x0f, 0x0c, 0x86, 0x0a, 0xd2, 0xdc, 0x39, 0x66, 0x70, 0x73, 0xc1, 0xa9, 0x06, 0x7c,
-	0x17, 0x6a, 0x62, 0x34, 0x50, 0x15, 0x9f, 0x9d, 0x26, 0x06, 0x9b, 0xf9, 0x4d, 0xad, 0xf5, 0x00,
-	0xea, 0xe2, 0x53, 0x51, 0x17, 0x40, 0xee, 0xcb, 0x71, 0xd0, 0xc9, 0xee, 0x9a, 0x2b, 0x0f, 0x4a,
-	0xca, 0x0e, 0xcd, 0xd9, 0xa1, 0xf3, 0xec, 0x64, 0x92, 0x33, 0xaa, 0xf3, 0x9f, 0x40, 0x1e, 0xfe,
-	0x13, 0x00, 0x00, 0xff, 0xff, 0xc7, 0xed, 0x36, 0x83, 0x0f, 0x11, 0x00, 0x00,
+	// 1516 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x09, 0x6e, 0x88, 0x02, 0xff, 0xb5, 0x58, 0xd9, 0x72, 0xdc, 0x44,
+	0x14, 0xf5, 0x8c, 0x34, 0xdb, 0x9d, 0xc5, 0xb6, 0xbc, 0x8d, 0x27, 0x84, 0x18, 0x25, 0x90, 0x14,
+	0x95, 0x72, 0x05, 0x87, 0x25, 0x84, 0x2a, 0x20, 0x98, 0x14, 0x81, 0x4a, 0xc0, 0xc4, 0x36, 0x55,
+	0xbc, 0x30, 0xa5, 0x91, 0x9a, 0x19, 0x61, 0x8d, 0x24, 0xd4, 0x2d, 0x2f, 0x55, 0x7c, 0x01, 0xbc,
+	0xf3, 0x17, 0x54, 0xf1, 0xc4, 0x07, 0xf0, 0x39, 0x7c, 0x05, 0x57, 0xbd, 0x68, 0x9b, 0xc5, 0xe4,
+	0x81, 0x17, 0x97, 0xbb, 0xfb, 0xde, 0x73, 0xcf, 0xdd, 0xba, 0x75, 0x07, 0x5a, 0x56, 0xe8, 0xee,
+	0x87, 0x51, 0xc0, 0x02, 0xa3, 0xc6, 0xae, 0x42, 0x42, 0xcd, 0x11, 0x6c, 0x9e, 0x86, 0x8e, 0xc5,
+	0xc8, 0x51, 0x14, 0xd8, 0x84, 0xd2, 0x97, 0xe4, 0xe7, 0x98, 0x50, 0x66, 0x00, 0x54, 0x5d, 0xa7,
+	0x5f, 0xd9, 0xab, 0xdc, 0x6b, 0x19, 0x6d, 0xd0, 0x42, 0x5c, 0x54, 0xf9, 0x02, 0x4f, 0x6c, 0x2f,
+	0xa0, 0xe4, 0x98, 0x39, 0xae, 0xdf, 0xd7, 0x70, 0xaf, 0x69, 0x74, 0xa1, 0x76, 0xe1, 0x3a, 0x6c,
+	0xd2, 0xd7, 0x71, 0xd9, 0x35, 0x7a, 0x50, 0x9f, 0x10, 0x77, 0x3c, 0x61, 0xfd, 0x5a, 0xb2, 0x36,
+	0x77, 0x60, 0xab, 0x64, 0x83, 0x86, 0x81, 0x4f, 0x89, 0xf9, 0x5b, 0x05, 0xb6, 0x0f, 0x23, 0x82,
+	0x27, 0x87, 0x81, 0xcf, 0x2c, 0xd7, 0x27, 0xd1, 0x3c, 0xfb, 0xb8, 0x18, 0xc5, 0xbe, 0xe3, 0x91,
+	0x23, 0x0b, 0x6d, 0x64, 0x34, 0x26, 0xc4, 0x3e, 0x0b, 0x03, 0xd7, 0x67, 0x9c, 0x46, 0x2b, 0xa1,
+	0x41, 0x39, 0x2b, 0x9d, 0x2f, 0x91, 0x06, 0x2e, 0x83, 0x58, 0xd0, 0x50, 0x6b, 0x12, 0x45, 0xfd,
+	0xba, 0x5a, 0x7b, 0xd6, 0x88, 0x78, 0xb4, 0xdf, 0xd8, 0xd3, 0xee, 0xb5, 0xcc, 0x8f, 0x61, 0x67,
+	0x86, 0x8c, 0x20, 0x6a, 0xdc, 0x86, 0x96, 0xad, 0x36, 0x39, 0xa9, 0xf6, 0xc1, 0xda, 0x3e, 0x0f,
+	0xe0, 0x7e, 0x2a, 0x6c, 0x3e, 0x82, 0xee, 0xb1, 0x3b, 0xf6, 0x2d, 0xef, 0xda, 0x18, 0x26, 0x4c,
+	0xb8, 0x24, 0x27, 0xde, 0x35, 0xd7, 0xa0, 0xa7, 0x34, 0x65, 0x64, 0xfe, 0xac, 0xc0, 0xfa, 0x13,
+	0xc7, 0x59, 0x92, 0x94, 0x35, 0x68, 0x32, 0x12, 0x4d, 0xdd, 0x04, 0xa5, 0xca, 0xb3, 0xb0, 0x0b,
+	0x7a, 0x4c, 0x91, 0x9f, 0xc6, 0xf9, 0xb5, 0x25, 0xbf, 0x53, 0xdc, 0x32, 0x3a, 0xa0, 0x5b, 0xd1,
+	0x98, 0x62, 0x60, 0x34, 0xc1, 0x85, 0xf8, 0xe7, 0x18, 0x15, 0xb9, 0xb0, 0x2f, 0x1c, 0x19, 0x12,
+	0xc9, 0xb2, 0x51, 0x0c, 0x67, 0xb3, 0x14, 0xce, 0x56, 0x29, 0x9c, 0x90, 0xac, 0xd1, 0x7d, 0x9d,
+	0xdb, 0x42, 0x8c, 0x58, 0xb2, 0xec, 0x26, 0x8b, 0xb1, 0x74, 0xbb, 0x6b, 0x6c, 0x43, 0xcf, 0x72,
+	0x1c, 0x97, 0xb9, 0x01, 0x92, 0xfe, 0xc2, 0x75, 0x28, 0x52, 0xd5, 0xd0, 0xfd, 0xbb, 0x60, 0xe4,
+	0x7d, 0x95, 0x31, 0x5f, 0x87, 0x16, 0xbd, 0xa2, 0x8c, 0x4c, 0x8f, 0x14, 0x9a, 0xf9, 0x3c, 0xcd,
+	0x50, 0x9a, 0xfa, 0x79, 0xa1, 0x79, 0xb3, 0x50, 0x1b, 0x55, 0x1e, 0x8e, 0x75, 0x95, 0xae, 0xf4,
+	0xc0, 0x1c, 0x40, 0x7f, 0x16, 0x4d, 0xc6, 0xff, 0x21, 0xec, 0x7c, 0x4e, 0x3c, 0x72, 0x9d, 0x25,
+	0x8c, 0xab, 0x6f, 0x4d, 0x89, 0x48, 0x6b, 0x02, 0x38, 0xab, 0x24, 0x01, 0x6f, 0xc3, 0xd6, 0x73,
+	0x97, 0xb2, 0xa5, 0x70, 0xe6, 0xf7, 0x00, 0x99, 0x40, 0x0a, 0x9e, 0x9a, 0x22, 0x97, 0x2e, 0x93,
+	0xb9, 0xc6, 0xb8, 0x32, 0x3b, 0x94, 0xed, 0xb7, 0x01, 0xed, 0xd8, 0x77, 0x2f, 0x8f, 0x03, 0xfb,
+	0x8c, 0x30, 0xca, 0xab, 0x9f, 0xf7, 0x24, 0x9d, 0x10, 0xcf, 0xe3, 0xc5, 0xdf, 0x34, 0x3f, 0x85,
+	0xed, 0xb2, 0x7d, 0x19, 0xe7, 0xb7, 0xa0, 0x9d, 0x45, 0x8b, 0xa2, 0x35, 0x6d, 0x51, 0xb8, 0x3a,
+	0xc7, 0x0c, 0xa3, 0x35, 0x8f, 0xf8, 0x1e, 0xf4, 0xd2, 0x3e, 0xe0, 0x42, 0xa2, 0x3a, 0x2c, 0x16,
+	0x53, 0x29, 0xf1, 0x47, 0x05, 0x1a, 0x32, 0xc3, 0xaa, 0xca, 0xfe, 0xc7, 0x3a, 0x2e, 0xd4, 0x4e,
+	0x83, 0x17, 0xdf, 0x2b, 0x56, 0xf3, 0x2f, 0xd0, 0x4a, 0x3d, 0xba, 0xf6, 0x32, 0x7a, 0x03, 0x5a,
+	0xa1, 0xf0, 0x8d, 0x88, 0x9a, 0x6e, 0x1f, 0xf4, 0x24, 0x6d, 0xe5, 0x73, 0x16, 0x0f, 0xbd, 0x74,
+	0xf9, 0x08, 0xfa, 0xe8, 0x59, 0x98, 0x74, 0x44, 0x5d, 0x76, 0x44, 0xe3, 0x85, 0x65, 0x4f, 0xd0,
+	0x78, 0x72, 0x60, 0x87, 0x32, 0x8c, 0xfc, 0x6a, 0x9d, 0x92, 0x69, 0x10, 0x5d, 0x71, 0xcb, 0xba,
+	0xf9, 0x1d, 0xde, 0x39, 0x22, 0x29, 0x32, 0x9b, 0x77, 0xb0, 0xf6, 0x15, 0x6f, 0x95, 0xcc, 0x99,
+	0xab, 0xca, 0xb8, 0x05, 0x8d, 0xa9, 0xc0, 0x97, 0xed, 0xa1, 0xe8, 0x4a, 0xab, 0xe6, 0x13, 0xd8,
+	0x16, 0x57, 0xf6, 0xd2, 0x8b, 0x79, 0xe6, 0x52, 0x13, 0x1e, 0xf2, 0xdb, 0xd8, 0xdc, 0x85, 0x9d,
+	0x19, 0x08, 0xd9, 0x0c, 0x26, 0x74, 0x9f, 0x9e, 0x13, 0xac, 0x36, 0x05, 0x8a, 0xf9, 0x62, 0xee,
+	0x14, 0xff, 0xb3, 0xa6, 0x21, 0xc7, 0xd6, 0xcd, 0x6f, 0xa1, 0xc6, 0x65, 0x92, 0x00, 0x24, 0xdc,
+	0xa4, 0x49, 0x61, 0x7e, 0x9e, 0xc5, 0xae, 0xa2, 0xa3, 0xab, 0x12, 0xc8, 0x20, 0x6b, 0x1c, 0xf2,
+	0xaf, 0x0a, 0x74, 0xbe, 0x26, 0xec, 0x22, 0x88, 0xce, 0x92, 0xa0, 0xd1, 0x52, 0x87, 0x61, 0x25,
+	0x46, 0x97, 0xc3, 0xd1, 0x15, 0xc3, 0x24, 0xf2, 0xe8, 0x26, 0xb9, 0xc6, 0x9d, 0x23, 0x4b, 0xf4,
+	0x95, 0xc6, 0xf7, 0x10, 0xf7, 0xe5, 0xe5, 0x10, 0x0b, 0x25, 0x88, 0x44, 0x2e, 0xb9, 0x18, 0x6e,
+	0x39, 0x51, 0x10, 0x86, 0xc4, 0x11, 0xb6, 0x12, 0xb0, 0x13, 0x05, 0x56, 0x57, 0x52, 0xb8, 0x13,
+	0x4a, 0xb0, 0x86, 0x02, 0x3b, 0x49, 0xc1, 0x9a, 0x39, 0x31, 0x05, 0xd6, 0xe2, 0xc4, 0xa7, 0xd0,
+	0x3c, 0x0c, 0xe3, 0x53, 0x6a, 0x8d, 0x49, 0xd2, 0xec, 0x2c, 0x60, 0x96, 0x37, 0x8c, 0x93, 0xa5,
+	0x08, 0x96, 0xb1, 0x09, 0x9d, 0x90, 0x44, 0x58, 0x27, 0x72, 0xb7, 0x8a, 0x79, 0xd7, 0x8d, 0x1b,
+	0xb0, 0xc1, 0x97, 0x43, 0xd7, 0x1f, 0x9e, 0x91, 0xc8, 0x27, 0xde, 0x34, 0x70, 0x88, 0xf4, 0x63,
+	0x17, 0xd6, 0xd3, 0xc3, 0xa4, 0xdd, 0xf8, 0x11, 0xf7, 0xc7, 0x3c, 0x81, 0xde, 0xc9, 0x04, 0x3f,
+	0x12, 0x98, 0xe7, 0xfa, 0xe3, 0xcf, 0x2d, 0x66, 0x19, 0xab, 0xd0, 0x40, 0x7c, 0x37, 0x70, 0xa8,
+	0x34, 0x88, 0xda, 0x4c, 0x88, 0x10, 0x67, 0xa8, 0x8e, 0x44, 0xd0, 0xf0, 0x96, 0xcf, 0x8e, 0x92,
+	0x14, 0x08, 0x83, 0xe6, 0x0f, 0xdc, 0x09, 0x11, 0x78, 0x13, 0xdf, 0xd3, 0x94, 0xac, 0x78, 0x4f,
+	0x57, 0x55, 0x91, 0x2a, 0x47, 0xf7, 0x61, 0x95, 0xa5, 0x2c, 0x86, 0x58, 0x48, 0x96, 0xac, 0xd5,
+	0x2d, 0x29, 0x59, 0xe4, 0x68, 0x7e, 0x02, 0xf0, 0x82, 0xb7, 0x06, 0x67, 0x8c, 0xed, 0x9e, 0x0f,
+	0x10, 0x06, 0x7a, 0x6a, 0x5d, 0xa6, 0xd1, 0x49, 0xb6, 0xd0, 0xa7, 0x1f, 0x2d, 0xd7, 0xb3, 0xe5,
+	0xe7, 0x83, 0x6e, 0xfe, 0x53, 0x81, 0xb6, 0x40, 0x10, 0x24, 0x11, 0xc2, 0xc6, 0x76, 0x50, 0x10,
+	0x7b, 0x0a, 0xb1, 0xf8, 0xa0, 0xe4, 0x6c, 0xe2, 0xbb, 0x43, 0x2f, 0xac, 0x50, 0x5a, 0xd1, 0x16,
+	0x89, 0xdd, 0x85, 0x8e, 0xc8, 0x86, 0x14, 0xd4, 0x17, 0x09, 0xde, 0x4f, 0xae, 0x2c, 0x64, 0xc2,
+	0xaf, 0x88, 0xf6, 0xc1, 0xcd, 0x82, 0x04, 0xe7, 0xb8, 0xcf, 0xff, 0x3e, 0xf5, 0x59, 0x74, 0x35,
+	0xb8, 0x0f, 0x90, 0xad, 0x92, 0x5e, 0x38, 0x23, 0x57, 0xb2, 0xb2, 0xd1, 0x93, 0x73, 0xcb, 0x8b,
+	0xa5, 0xe7, 0x8f, 0xab, 0x8f, 0x2a, 0xe6, 0x57, 0xb0, 0xfa, 0x99, 0x77, 0xe6, 0x06, 0x39, 0x15,
+	0x94, 0x9a, 0x5a, 0x3f, 0x05, 0x91, 0xf4, 0x37, 0x59, 0xba, 0x3e, 0x2e, 0x45, 0xb8, 0xb0, 0xf1,
+	0x82, 0x30, 0xfb, 0xd0, 0x12, 0x78, 0xa2, 0x5e, 0xfe, 0xd6, 0x00, 0x32, 0x30, 0xe3, 0x31, 0x0c,
+	0xdc, 0x60, 0x88, 0x25, 0x75, 0xee, 0xda, 0x44, 0xb4, 0xc0, 0x30, 0x22, 0x76, 0x1c, 0x51, 0xf7,
+	0x9c, 0xc8, 0x2b, 0x69, 0x5b, 0xfa, 0x52, 0xe6, 0xf0, 0x1e, 0x6c, 0x65, 0xba, 0x4e, 0x4e, 0xad,
+	0xba, 0x54, 0xed, 0x21, 0x6c, 0xa0, 0x1a, 0xde, 0x25, 0x71, 0x41, 0x49, 0x5b, 0xaa, 0xf4, 0x21,
+	0xec, 0xe6, 0x78, 0x26, 0x95, 0x9a, 0x53, 0xd5, 0x97, 0xaa, 0xbe, 0x0f, 0xdb, 0xa8, 0x7a, 0x61,
+	0xb9, 0xac, 0xac, 0x57, 0xfb, 0x0f, 0x3c, 0xa7, 0x24, 0x1a, 0x17, 0x78, 0xd6, 0x97, 0x2a, 0xbd,
+	0x03, 0xeb, 0xa8, 0x54, 0xb2, 0xd3, 0xb8, 0x4e, 0x85, 0x12, 0x9b, 0xe1, 0xad, 0x92, 0x53, 0x69,
+	0x2e, 0x53, 0xc1, 0x1b, 0xbf, 0xf3, 0x2c, 0x1e, 0x13, 0xe6, 0x8d, 0xd2, 0xea, 0x7f, 0xd5, 0x06,
+	0xfa, 0xb5, 0x0a, 0xed, 0xc3, 0x71, 0x14, 0xc4, 0x61, 0xa1, 0xcb, 0x45, 0x0d, 0xcf, 0x74, 0xb9,
+	0x90, 0xb9, 0x07, 0x1d, 0xf1, 0xa0, 0x49, 0x31, 0xd1, 0x5c, 0xc6, 0x6c, 0xa9, 0x27, 0xdf, 0x29,
+	0xa3, 0x84, 0xb3, 0x14, 0x2c, 0xb6, 0x57, 0xae, 0xfc, 0x3e, 0x82, 0xee, 0x44, 0x38, 0x22, 0x25,
+	0x45, 0x2a, 0xef, 0x28, 0xcb, 0x19, 0xc1, 0xfd, 0xbc, 0xc3, 0xa2, 0x89, 0x9e, 0xc1, 0xfa, 0xcc,
+	0x66, 0xb1, 0x97, 0xcc, 0x7c, 0x2f, 0xb5, 0x0f, 0x36, 0x24, 0x6c, 0x5e, 0x8b, 0x37, 0xd8, 0xa5,
+	0x78, 0x99, 0xb3, 0xef, 0xd9, 0xb7, 0xa1, 0xeb, 0x8b, 0xc7, 0x27, 0x8d, 0x88, 0x96, 0x03, 0x28,
+	0x3c, 0x4c, 0x18, 0x15, 0x9b, 0xf3, 0x9c, 0x1b, 0x95, 0x7c, 0x8c, 0x0b, 0xcf, 0x9c, 0x48, 0x83,
+	0xfc, 0x50, 0x9b, 0x37, 0x35, 0x1c, 0xfc, 0x5e, 0x07, 0xed, 0xc9, 0xd1, 0x97, 0xc6, 0x4b, 0x58,
+	0x2d, 0xcd, 0x3a, 0x86, 0xba, 0x5e, 0xe6, 0x0f, 0x64, 0x83, 0xd7, 0x17, 0x1d, 0xcb, 0x37, 0x7d,
+	0x25, 0xc1, 0x2c, 0x3d, 0xf8, 0x29, 0xe6, 0xfc, 0x6f, 0x89, 0x14, 0x73, 0xd1, 0x77, 0xc2, 0x8a,
+	0xf1, 0x01, 0xd4, 0xc5, 0x64, 0x64, 0x6c, 0x4a, 0xd9, 0xc2, 0x88, 0x35, 0xd8, 0x2a, 0xed, 0xa6,
+	0x8a, 0xcf, 0xa1, 0x5b, 0x98, 0x39, 0x8d, 0x1b, 0x05, 0x5b, 0xc5, 0xc1, 0x6a, 0xf0, 0xda, 0xfc,
+	0xc3, 0x14, 0xed, 0x10, 0x20, 0x9b, 0x50, 0x8c, 0xbe, 0x94, 0x9e, 0x19, 0xd0, 0x06, 0xbb, 0x73,
+	0x4e, 0x52, 0x90, 0x53, 0x58, 0x2b, 0xcf, 0x1b, 0x46, 0x29, 0xaa, 0xe5, 0xe9, 0x60, 0x70, 0x6b,
+	0xe1, 0x79, 0x1e, 0xb6, 0x3c, 0x75, 0xa4, 0xb0, 0x0b, 0x66, 0x98, 0x14, 0x76, 0xe1, 0xb8, 0xb2,
+	0x62, 0x7c, 0x03, 0xbd, 0xe2, 0xc0, 0x60, 0xa8, 0x20, 0xcd, 0x9d, 0x63, 0x06, 0x37, 0x17, 0x9c,
+	0xa6, 0x80, 0xef, 0x42, 0x4d, 0x8c, 0x06, 0xaa, 0xe2, 0xf3, 0xd3, 0xc4, 0x60, 0xb3, 0xb8, 0x99,
+	0x6a, 0x3d, 0x80, 0xba, 0xf8, 0x54, 0x4c, 0x0b, 0xa0, 0xf0, 0xe5, 0x38, 0xe8, 0xe4, 0x77, 0xcd,
+	0x95, 0x07, 0x15, 0x65, 0x87, 0x16, 0xec, 0xd0, 0x79, 0x76, 0x72, 0xc9, 0x19, 0xd5, 0xf9, 0xaf,
+	0x22, 0x0f, 0xff, 0x05, 0x3b, 0xeb, 0xa1, 0x0e, 0x22, 0x11, 0x00, 0x00,
 }
@@ -6,8 +6,9 @@ import (
 )
 
 // Notifier can effectively notify you about receiving from particular channels.
-// It operates with pairs <-chan struct{} <-> string which is notification
-// channel and its identificator respectively.
+// It operates with pairs <-chan struct{} <-> key which is notification
+// channel and its identificator respectively. A key can be any comparable
+// value such as a container id or the path of a cgroup event_control file.
 // Notification channel is <-chan struc{}, each send to which is spawn
 // notification from Notifier, close doesn't spawn anything and removes channel
 // from Notifier.
@@ -15,8 +16,17 @@ type Notifier struct {
 	c chan interface{}
 
 	m      sync.Mutex // guards doneCh
-	doneCh map[interface{}]chan struct{}
+	doneCh map[interface{}]*worker
 	closed bool
+	// wg tracks the workers so that c is only closed once they all returned
+	wg sync.WaitGroup
+}
+
+// worker forwards the notifications of a notification channel until done is
+// closed, exited is closed when it returned
+type worker struct {
+	done   chan struct{}
+	exited chan struct{}
 }
 
 // New returns a new notifier. A notifier must be closed by
@@ -24,7 +34,7 @@ type Notifier struct {
 func New() *Notifier {
 	s := &Notifier{
 		c:      make(chan interface{}),
-		doneCh: make(map[interface{}]chan struct{}),
+		doneCh: make(map[interface{}]*worker),
 	}
 	return s
 }
@@ -48,34 +58,66 @@ func (n *Notifier) Add(id interface{}, ch <-chan struct{}) error {
 		return errors.New("cannot register duplicate key")
 	}
 
-	done := make(chan struct{})
-	n.doneCh[id] = done
+	w := &worker{
+		done:   make(chan struct{}),
+		exited: make(chan struct{}),
+	}
+	n.doneCh[id] = w
 
-	n.startWorker(ch, id, done)
+	n.startWorker(ch, id, w)
 	return nil
 }
 
-func (n *Notifier) killWorker(id interface{}, done chan struct{}) {
+// Remove stops watching the notification channel registered for id.
+// The notification channel is not closed and the id can be added again.
+// Remove returns once the channel is no longer received from so that a
+// notification sent afterwards is left for the channel's next registration.
+func (n *Notifier) Remove(id interface{}) error {
 	n.m.Lock()
+	w, ok := n.doneCh[id]
+	if !ok {
+		n.m.Unlock()
+		return errors.New("cannot remove unregistered key")
+	}
 	delete(n.doneCh, id)
+	close(w.done)
+	n.m.Unlock()
+	<-w.exited
+	return nil
+}
+
+func (n *Notifier) killWorker(id interface{}, w *worker) {
+	n.m.Lock()
+	// the id may have been removed and registered again with a new worker
+	if n.doneCh[id] == w {
+		delete(n.doneCh, id)
+	}
 	n.m.Unlock()
 }
 
-func (n *Notifier) startWorker(ch <-chan struct{}, id interface{}, done chan struct{}) {
+func (n *Notifier) startWorker(ch <-chan struct{}, id interface{}, w *worker) {
+	done := w.done
+	n.wg.Add(1)
 	go func() {
+		defer n.wg.Done()
+		defer close(w.exited)
 		for {
 			select {
 			case _, ok := <-ch:
 				if !ok {
 					// If the channel is closed, we don't need the goroutine
 					// or the done channel mechanism running anymore.
-					n.killWorker(id, done)
+					n.killWorker(id, w)
+					return
+				}
+				select {
+				case n.c <- id:
+				case <-done:
 					return
 				}
-				n.c <- id
 			case <-done:
 				// We don't need this goroutine running anymore, return.
-				n.killWorker(id, done)
+				n.killWorker(id, w)
 				return
 			}
 		}
@@ -85,10 +127,18 @@ func (n *Notifier) startWorker(ch <-chan struct{}, id interface{}, done chan str
 // Close closes the notifier and releases its underlying resources.
 func (n *Notifier) Close() {
 	n.m.Lock()
-	defer n.m.Unlock()
-	for _, done := range n.doneCh {
-		close(done)
+	if n.closed {
+		n.m.Unlock()
+		return
+	}
+	for id, w := range n.doneCh {
+		delete(n.doneCh, id)
+		close(w.done)
 	}
-	close(n.c)
 	n.closed = true
+	n.m.Unlock()
+	// wait for the workers blocked on sending a notification before closing
+	// the channel so that they can not send on a closed channel
+	n.wg.Wait()
+	close(n.c)
 }
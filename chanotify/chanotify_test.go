@@ -1,6 +1,7 @@
 package chanotify
 
 import (
+	"runtime"
 	"sync"
 	"testing"
 	"time"
@@ -115,3 +116,71 @@ func TestAddDuplicate(t *testing.T) {
 		t.Fatalf("duplicate keys are not allowed; but Add succeeded")
 	}
 }
+
+func TestRemove(t *testing.T) {
+	s := New()
+	ch := make(chan struct{}, 1)
+	if err := s.Add("1", ch); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Remove("1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Remove("1"); err == nil {
+		t.Fatal("removing an unregistered key should fail")
+	}
+	// the key can be registered again once removed
+	if err := s.Add("1", ch); err != nil {
+		t.Fatal(err)
+	}
+	ch <- struct{}{}
+	if got, want := <-s.Chan(), "1"; got != want {
+		t.Fatalf("got %v; want %v", got, want)
+	}
+	s.Close()
+}
+
+func TestConcurrentAddRemove(t *testing.T) {
+	before := runtime.NumGoroutine()
+	s := New()
+	received := make(chan struct{})
+	go func() {
+		for range s.Chan() {
+		}
+		close(received)
+	}()
+	var wg sync.WaitGroup
+	for i := 0; i < 256; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ch := make(chan struct{}, 4)
+			for j := 0; j < 4; j++ {
+				if err := s.Add(i, ch); err != nil {
+					t.Error(err)
+					return
+				}
+				ch <- struct{}{}
+				if err := s.Remove(i); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	s.m.Lock()
+	if len(s.doneCh) != 0 {
+		t.Fatalf("want 0 channels, got %d", len(s.doneCh))
+	}
+	s.m.Unlock()
+	s.Close()
+	<-received
+	// give the exited goroutines time to be accounted for
+	for i := 0; i < 10 && runtime.NumGoroutine() > before; i++ {
+		time.Sleep(100 * time.Millisecond)
+	}
+	if after := runtime.NumGoroutine(); after > before {
+		t.Fatalf("leaked %d goroutines", after-before)
+	}
+}
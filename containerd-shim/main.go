@@ -6,16 +6,21 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/docker/containerd/osutils"
+	"github.com/docker/containerd/runtime"
 	"github.com/docker/docker/pkg/term"
 )
 
+// setupLogger writes the shim's log to the log file in the process's state
+// directory, containerd reports the log when the process fails to start
 func setupLogger() {
-	f, err := os.OpenFile("/tmp/shim.log", os.O_CREATE|os.O_RDWR|os.O_APPEND, 0755)
+	f, err := os.OpenFile(runtime.ShimLogFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
 	if err != nil {
-		panic(err)
+		// keep logging to stderr
+		return
 	}
 	logrus.SetOutput(f)
 }
@@ -27,6 +32,7 @@ func setupLogger() {
 // to the state directory where the shim can locate fifos and other information.
 func main() {
 	flag.Parse()
+	setupLogger()
 	// start handling signals as soon as possible so that things are properly reaped
 	// or if runc exits before we hit the handler
 	signals := make(chan os.Signal, 2048)
@@ -94,12 +100,7 @@ func main() {
 						"pid":    e.Pid,
 						"status": e.Status,
 					}).Info("shim: runc exited")
-					if err := writeInt("exitStatus", e.Status); err != nil {
-						logrus.WithFields(logrus.Fields{
-							"error":  err,
-							"status": e.Status,
-						}).Error("shim: write exit status")
-					}
+					writeExitStatus(e.Status)
 				}
 			}
 		}
@@ -116,12 +117,59 @@ func main() {
 	}
 }
 
+// exitStatusRetryInterval is how long the shim waits before retrying a write of the
+// exit status that failed because the filesystem is full
+var exitStatusRetryInterval = 1 * time.Second
+
+// writeExitStatus writes runc's exit status for containerd to read.  While the
+// filesystem is full the write is retried so that the exit is not lost.
+func writeExitStatus(status int) {
+	for {
+		err := writeInt("exitStatus", status)
+		if err == nil {
+			return
+		}
+		logrus.WithFields(logrus.Fields{
+			"error":  err,
+			"status": status,
+		}).Error("shim: write exit status")
+		if !isNoSpace(err) {
+			return
+		}
+		time.Sleep(exitStatusRetryInterval)
+	}
+}
+
+// writeInt writes i to a temporary file that is renamed to path so that a failed
+// write never leaves a truncated file for containerd to read
 func writeInt(path string, i int) error {
-	f, err := os.Create(path)
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
 	if err != nil {
 		return err
 	}
-	defer f.Close()
 	_, err = fmt.Fprintf(f, "%d", i)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		os.Remove(tmp)
+	}
 	return err
 }
+
+// isNoSpace returns true if the error was caused by the filesystem being full
+func isNoSpace(err error) bool {
+	switch e := err.(type) {
+	case *os.PathError:
+		err = e.Err
+	case *os.LinkError:
+		err = e.Err
+	case *os.SyscallError:
+		err = e.Err
+	}
+	return err == syscall.ENOSPC
+}
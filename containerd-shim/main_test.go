@@ -0,0 +1,77 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+)
+
+// tempDir changes into a new temporary directory and returns a func that restores
+// the working directory and removes it
+func tempDir(t *testing.T) func() {
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, err := ioutil.TempDir("", "containerd-shim")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	return func() {
+		os.Chdir(cwd)
+		os.RemoveAll(dir)
+	}
+}
+
+func TestWriteIntNoSpace(t *testing.T) {
+	defer tempDir(t)()
+	// writes to /dev/full fail with ENOSPC
+	if err := os.Symlink("/dev/full", "exitStatus.tmp"); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeInt("exitStatus", 137); !isNoSpace(err) {
+		t.Fatalf("expected ENOSPC but received %v", err)
+	}
+	if _, err := os.Stat("exitStatus"); !os.IsNotExist(err) {
+		t.Fatalf("expected no exit status file but received %v", err)
+	}
+	if _, err := os.Lstat("exitStatus.tmp"); !os.IsNotExist(err) {
+		t.Fatalf("expected the temporary file to be removed but received %v", err)
+	}
+}
+
+func TestWriteExitStatusRetriesNoSpace(t *testing.T) {
+	defer tempDir(t)()
+	defer func(d time.Duration) { exitStatusRetryInterval = d }(exitStatusRetryInterval)
+	exitStatusRetryInterval = 10 * time.Millisecond
+	if err := os.Symlink("/dev/full", "exitStatus.tmp"); err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan struct{})
+	go func() {
+		writeExitStatus(137)
+		close(done)
+	}()
+	// the symlink is removed by the failed write so recreate it to keep the
+	// filesystem full for a few retries
+	for i := 0; i < 3; i++ {
+		time.Sleep(exitStatusRetryInterval)
+		os.Symlink("/dev/full", "exitStatus.tmp")
+	}
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("exit status was not written after the filesystem had space")
+	}
+	data, err := ioutil.ReadFile("exitStatus")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "137" {
+		t.Fatalf("expected exit status 137 but received %q", data)
+	}
+}
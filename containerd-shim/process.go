@@ -38,10 +38,15 @@ func newProcess(id, bundle string) (*process, error) {
 	}
 	p.state = s
 	if s.Checkpoint != "" {
-		cpt, err := loadCheckpoint(bundle, s.Checkpoint)
+		dir := s.CheckpointPath
+		if dir == "" {
+			dir = filepath.Join(bundle, "checkpoints")
+		}
+		cpt, err := runtime.LoadCheckpoint(dir, s.Checkpoint)
 		if err != nil {
 			return nil, err
 		}
+		cpt.Path = dir
 		p.checkpoint = cpt
 	}
 	if err := p.openIO(); err != nil {
@@ -63,58 +68,30 @@ func loadProcess() (*runtime.ProcessState, error) {
 	return &s, nil
 }
 
-func loadCheckpoint(bundle, name string) (*runtime.Checkpoint, error) {
-	f, err := os.Open(filepath.Join(bundle, "checkpoints", name, "config.json"))
-	if err != nil {
-		return nil, err
-	}
-	defer f.Close()
-	var cpt runtime.Checkpoint
-	if err := json.NewDecoder(f).Decode(&cpt); err != nil {
-		return nil, err
-	}
-	return &cpt, nil
-}
-
 func (p *process) start() error {
 	cwd, err := os.Getwd()
 	if err != nil {
 		return err
 	}
-	args := []string{}
+	var (
+		driver  = p.driver()
+		pidFile = filepath.Join(cwd, "pid")
+		cmd     *exec.Cmd
+	)
 	if p.state.Exec {
-		args = append(args, "exec",
-			"--process", filepath.Join(cwd, "process.json"),
-			"--console", p.consolePath,
-		)
-	} else if p.checkpoint != nil {
-		args = append(args, "restore",
-			"--image-path", filepath.Join(p.bundle, "checkpoints", p.checkpoint.Name),
-		)
-		add := func(flags ...string) {
-			args = append(args, flags...)
-		}
-		if p.checkpoint.Shell {
-			add("--shell-job")
-		}
-		if p.checkpoint.Tcp {
-			add("--tcp-established")
-		}
-		if p.checkpoint.UnixSockets {
-			add("--ext-unix-sk")
-		}
+		cmd = driver.Exec(p.id, runtime.ExecOpts{
+			Process: filepath.Join(cwd, "process.json"),
+			Console: p.consolePath,
+			PidFile: pidFile,
+		})
 	} else {
-		args = append(args, "start",
-			"--bundle", p.bundle,
-			"--console", p.consolePath,
-		)
+		cmd = driver.Create(p.id, runtime.CreateOpts{
+			Bundle:     p.bundle,
+			Console:    p.consolePath,
+			PidFile:    pidFile,
+			Checkpoint: p.checkpoint,
+		})
 	}
-	args = append(args,
-		"-d",
-		"--pid-file", filepath.Join(cwd, "pid"),
-		p.id,
-	)
-	cmd := exec.Command("runc", args...)
 	cmd.Dir = p.bundle
 	cmd.Stdin = p.stdio.stdin
 	cmd.Stdout = p.stdio.stdout
@@ -124,9 +101,17 @@ func (p *process) start() error {
 	cmd.SysProcAttr = &syscall.SysProcAttr{
 		Pdeathsig: syscall.SIGKILL,
 	}
+	if err := writeInvocation(cmd); err != nil {
+		return err
+	}
 	if err := cmd.Run(); err != nil {
 		return err
 	}
+	if !p.state.Exec {
+		if err := driver.Start(p.id); err != nil {
+			return err
+		}
+	}
 	data, err := ioutil.ReadFile("pid")
 	if err != nil {
 		return err
@@ -140,13 +125,39 @@ func (p *process) start() error {
 
 }
 
+// writeInvocation records the runtime command so that the start of the process
+// can be reproduced
+func writeInvocation(cmd *exec.Cmd) error {
+	f, err := os.Create(runtime.InvocationFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	env := cmd.Env
+	if env == nil {
+		// the runtime inherits the shim's environment
+		env = os.Environ()
+	}
+	return json.NewEncoder(f).Encode(runtime.Invocation{
+		Path: cmd.Path,
+		Args: cmd.Args,
+		Env:  env,
+		Dir:  cmd.Dir,
+	})
+}
+
+// driver returns the driver for the runtime that runs the process
+func (p *process) driver() runtime.RuntimeDriver {
+	return runtime.NewDriver(p.state.Runtime, p.state.RuntimeArgs)
+}
+
 func (p *process) pid() int {
 	return p.containerPid
 }
 
 func (p *process) delete() error {
 	if !p.state.Exec {
-		return exec.Command("runc", "delete", p.id).Run()
+		return p.driver().Delete(p.id)
 	}
 	return nil
 }
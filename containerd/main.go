@@ -6,6 +6,7 @@ import (
 	"os"
 	"os/signal"
 	"runtime"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
@@ -53,10 +54,174 @@ var daemonFlags = []cli.Flag{
 		Name:  "oom-notify",
 		Usage: "enable oom notifications for containers",
 	},
+	cli.StringSliceFlag{
+		Name:  "memory-pressure",
+		Value: &cli.StringSlice{},
+		Usage: "emit events when the memory pressure of a container reaches the level, low, medium or critical",
+	},
+	cli.DurationFlag{
+		Name:  "liveness-interval",
+		Usage: "interval to check that the init process of containers is alive, zero disables the checks",
+	},
 	cli.StringFlag{
 		Name:  "graphite-address",
 		Usage: "Address of graphite server",
 	},
+	cli.DurationFlag{
+		Name:  "oom-debounce",
+		Usage: "collapse oom notifications for a container received within this window into a single event",
+	},
+	cli.DurationFlag{
+		Name:  "memory-pressure-debounce",
+		Usage: "collapse memory pressure notifications for a container and level received within this window into a single event",
+	},
+	cli.IntFlag{
+		Name:  "journal-flush-events",
+		Value: 1,
+		Usage: "number of buffered events after which the event journal is flushed",
+	},
+	cli.StringFlag{
+		Name:  "journal-dir",
+		Usage: "directory for the event journal (defaults to the state directory)",
+	},
+	cli.DurationFlag{
+		Name:  "journal-flush-interval",
+		Usage: "interval for flushing buffered events to the event journal (0 disables)",
+	},
+	cli.IntFlag{
+		Name:  "journal-max-size",
+		Usage: "size in bytes at which the event journal is rotated (0 is unlimited)",
+	},
+	cli.IntFlag{
+		Name:  "journal-max-events",
+		Usage: "number of events at which the event journal is rotated (0 is unlimited)",
+	},
+	cli.IntFlag{
+		Name:  "journal-segments",
+		Value: 1,
+		Usage: "number of rotated event journal segments to keep",
+	},
+	cli.IntFlag{
+		Name:  "journal-buffer-limit",
+		Value: 4 * 1024 * 1024,
+		Usage: "size in bytes of the events kept for the event journal while they cannot be written (0 is unlimited)",
+	},
+	cli.IntFlag{
+		Name:  "max-event-size",
+		Usage: "maximum size in bytes of an event before its metadata is truncated (0 is unlimited)",
+	},
+	cli.StringSliceFlag{
+		Name:  "runtime",
+		Value: &cli.StringSlice{},
+		Usage: "OCI runtime that containers may select, as name=path",
+	},
+	cli.StringSliceFlag{
+		Name:  "allow-runtime-arg",
+		Value: &cli.StringSlice{},
+		Usage: "runtime flag that containers may be started with",
+	},
+	cli.StringSliceFlag{
+		Name:  "checkpoint-root",
+		Value: &cli.StringSlice{},
+		Usage: "directory that checkpoints with a custom path may be stored under",
+	},
+	cli.DurationFlag{
+		Name:  "pause-timeout",
+		Usage: "abort pausing or resuming a container after this duration (0 waits forever)",
+	},
+	cli.StringFlag{
+		Name:  "init-path",
+		Usage: "path of the init binary injected into containers that request an init process",
+	},
+	cli.Float64Flag{
+		Name:  "restore-rate",
+		Usage: "maximum number of containers restored per second on startup (0 is unlimited)",
+	},
+	cli.Float64Flag{
+		Name:  "event-lag-warning",
+		Usage: "fraction of a subscriber's event buffer at which it is sent a lag-warning event (0 disables warnings)",
+	},
+	cli.IntFlag{
+		Name:  "restore-retries",
+		Usage: "number of times to retry loading a container's state on restore before quarantining it",
+	},
+	cli.BoolFlag{
+		Name:  "strict-restore",
+		Usage: "fail to start if a container fails to restore instead of quarantining it",
+	},
+	cli.BoolFlag{
+		Name:  "move-corrupt",
+		Usage: "move the state of containers that fail to restore to the corrupt directory of the state directory",
+	},
+	cli.DurationFlag{
+		Name:  "restore-backoff",
+		Value: 100 * time.Millisecond,
+		Usage: "time to wait before the first retry of loading a container's state, doubled for each retry",
+	},
+	cli.IntFlag{
+		Name:  "max-event-subscribers",
+		Usage: "maximum number of event subscribers (0 is unlimited)",
+	},
+	cli.IntFlag{
+		Name:  "exec-limit",
+		Usage: "maximum number of exec processes started at the same time across all containers, 0 starts them one at a time",
+	},
+	cli.BoolFlag{
+		Name:  "exec-queue",
+		Usage: "queue exec requests over the exec limit instead of rejecting them",
+	},
+	cli.IntFlag{
+		Name:  "stats-workers",
+		Value: 4,
+		Usage: "number of workers collecting stats for stats requests",
+	},
+	cli.IntFlag{
+		Name:  "stats-queue",
+		Value: 256,
+		Usage: "number of stats requests queued for the stats workers before requests are rejected",
+	},
+	cli.IntFlag{
+		Name:  "stats-concurrency",
+		Usage: "maximum number of containers whose stats are collected in the background at the same time (0 is unlimited)",
+	},
+	cli.DurationFlag{
+		Name:  "stats-interval",
+		Usage: "default interval for collecting container stats in the background (0 disables)",
+	},
+	cli.StringFlag{
+		Name:  "otlp-endpoint",
+		Usage: "OpenTelemetry collector http endpoint to export events to",
+	},
+	cli.StringFlag{
+		Name:  "event-socket",
+		Usage: "unix datagram socket to write events to as json",
+	},
+	cli.IntFlag{
+		Name:  "exec-capture-limit",
+		Value: 64 * 1024,
+		Usage: "maximum number of bytes captured from each output stream of an exec process",
+	},
+	cli.StringFlag{
+		Name:  "stop-policy",
+		Value: string(supervisor.StopPolicyLeaveRunning),
+		Usage: "what to do with containers when the daemon exits (leave-running or kill)",
+	},
+	cli.StringFlag{
+		Name:  "missing-bundle",
+		Usage: "what to do with containers whose bundle is missing on restore (quarantine or record)",
+	},
+	cli.BoolFlag{
+		Name:  "ordered-stop",
+		Usage: "kill containers one at a time by stop priority and reverse start order when the daemon exits",
+	},
+	cli.BoolFlag{
+		Name:  "reap-cgroups",
+		Usage: "remove empty cgroups of containers that no longer exist on startup",
+	},
+	cli.StringFlag{
+		Name:  "cgroup-parent",
+		Usage: "cgroup containing the container cgroups to reap (defaults to containerd's cgroup)",
+	},
 }
 
 func main() {
@@ -78,11 +243,67 @@ func main() {
 		return nil
 	}
 	app.Action = func(context *cli.Context) {
+		opts := []supervisor.Option{
+			supervisor.WithStatsInterval(context.Duration("stats-interval")),
+			supervisor.WithStatsConcurrency(context.Int("stats-concurrency")),
+			supervisor.WithStatsWorkers(context.Int("stats-workers"), context.Int("stats-queue")),
+			supervisor.WithExecLimit(context.Int("exec-limit"), context.Bool("exec-queue")),
+			supervisor.WithOOMDebounce(context.Duration("oom-debounce")),
+			supervisor.WithMemoryPressure(context.StringSlice("memory-pressure")...),
+			supervisor.WithMemoryPressureDebounce(context.Duration("memory-pressure-debounce")),
+			supervisor.WithLivenessInterval(context.Duration("liveness-interval")),
+			supervisor.WithAllowedRuntimeArgs(context.StringSlice("allow-runtime-arg")...),
+			supervisor.WithCheckpointRoots(context.StringSlice("checkpoint-root")...),
+			supervisor.WithStopPolicy(supervisor.StopPolicy(context.String("stop-policy"))),
+			supervisor.WithMissingBundlePolicy(supervisor.MissingBundlePolicy(context.String("missing-bundle"))),
+			supervisor.WithExecCaptureLimit(context.Int("exec-capture-limit")),
+			supervisor.WithMaxSubscribers(context.Int("max-event-subscribers")),
+			supervisor.WithPauseTimeout(context.Duration("pause-timeout")),
+			supervisor.WithInitWrapper(context.String("init-path")),
+			supervisor.WithRestoreRate(context.Float64("restore-rate")),
+			supervisor.WithRestoreRetry(context.Int("restore-retries"), context.Duration("restore-backoff")),
+			supervisor.WithStrictRestore(context.Bool("strict-restore")),
+			supervisor.WithMoveCorrupt(context.Bool("move-corrupt")),
+			supervisor.WithMaxEventSize(context.Int("max-event-size")),
+			supervisor.WithLagWarning(context.Float64("event-lag-warning")),
+			supervisor.WithJournalDir(context.String("journal-dir")),
+			supervisor.WithJournalFlush(context.Int("journal-flush-events"), context.Duration("journal-flush-interval")),
+			supervisor.WithJournalRotation(int64(context.Int("journal-max-size")), context.Int("journal-max-events"), context.Int("journal-segments")),
+			supervisor.WithJournalBufferLimit(context.Int("journal-buffer-limit")),
+		}
+		if endpoint := context.String("otlp-endpoint"); endpoint != "" {
+			opts = append(opts, supervisor.WithOTLPExporter(endpoint))
+		}
+		if path := context.String("event-socket"); path != "" {
+			sink, err := supervisor.NewUnixgramSink(path)
+			if err != nil {
+				logrus.Fatal(err)
+			}
+			opts = append(opts, supervisor.WithEventSink(sink))
+		}
+		if runtimes := context.StringSlice("runtime"); len(runtimes) > 0 {
+			m := make(map[string]string, len(runtimes))
+			for _, r := range runtimes {
+				parts := strings.SplitN(r, "=", 2)
+				if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+					logrus.Fatalf("invalid runtime %q, expected name=path", r)
+				}
+				m[parts[0]] = parts[1]
+			}
+			opts = append(opts, supervisor.WithRuntimes(m))
+		}
+		if context.Bool("ordered-stop") {
+			opts = append(opts, supervisor.WithOrderedStop())
+		}
+		if context.Bool("reap-cgroups") {
+			opts = append(opts, supervisor.WithCgroupReaping(context.String("cgroup-parent")))
+		}
 		if err := daemon(
 			context.String("listen"),
 			context.String("state-dir"),
 			10,
 			context.Bool("oom-notify"),
+			opts...,
 		); err != nil {
 			logrus.Fatal(err)
 		}
@@ -161,11 +382,11 @@ func processMetrics() {
 	}()
 }
 
-func daemon(address, stateDir string, concurrency int, oom bool) error {
+func daemon(address, stateDir string, concurrency int, oom bool, opts ...supervisor.Option) error {
 	// setup a standard reaper so that we don't leave any zombies if we are still alive
 	// this is just good practice because we are spawning new processes
 	go reapProcesses()
-	sv, err := supervisor.New(stateDir, oom)
+	sv, err := supervisor.New(stateDir, oom, opts...)
 	if err != nil {
 		return err
 	}
@@ -1,6 +1,15 @@
 package eventloop
 
-import "sync"
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// ErrLoopClosed is returned for events sent after the loop started draining.
+var ErrLoopClosed = errors.New("eventloop: loop is closed")
 
 // Event is receiving notification from loop with Handle() call.
 type Event interface {
@@ -16,31 +25,149 @@ type EventLoop interface {
 }
 
 // ChanLoop is implementation of EventLoop based on channels.
+// Events sent with SendPriority are handled before the events sent with Send,
+// events of the same priority are handled in the order they were sent.
 type ChanLoop struct {
-	events chan Event
-	once   sync.Once
+	events   chan queuedEvent
+	priority chan queuedEvent
+	once     sync.Once
+	// dispatched is called with the time each event waited in the queue
+	dispatched func(time.Duration)
+
+	m      sync.Mutex // guards closed
+	closed bool
+	// senders are the sends in progress, they are waited for by Drain so that
+	// no event is queued after the loop stops
+	senders sync.WaitGroup
+	stopped chan struct{}
+}
+
+// queuedEvent is an event with the time it was sent
+type queuedEvent struct {
+	ev   Event
+	sent time.Time
+	// stop is queued by Drain after the events to handle before the loop stops
+	stop bool
 }
 
 // NewChanLoop returns ChanLoop with internal channel buffer set to q.
 func NewChanLoop(q int) EventLoop {
+	return NewObservedChanLoop(q, nil)
+}
+
+// NewObservedChanLoop returns ChanLoop with internal channel buffer set to q
+// which calls dispatched with the time that each event waited in the buffer
+// before it is handled.
+func NewObservedChanLoop(q int, dispatched func(wait time.Duration)) *ChanLoop {
 	return &ChanLoop{
-		events: make(chan Event, q),
+		events:     make(chan queuedEvent, q),
+		priority:   make(chan queuedEvent, q),
+		dispatched: dispatched,
+		stopped:    make(chan struct{}),
 	}
 }
 
-// Start starting to read events from channel in separate goroutines.
+// Start starting to read events from channel in separate goroutines until the
+// loop is drained.
 // All calls after first is no-op.
 func (el *ChanLoop) Start() error {
 	go el.once.Do(func() {
-		for ev := range el.events {
-			ev.Handle()
+		for {
+			var qe queuedEvent
+			select {
+			case qe = <-el.priority:
+			default:
+				select {
+				case qe = <-el.priority:
+				case qe = <-el.events:
+				}
+			}
+			if qe.stop {
+				el.stop()
+				return
+			}
+			el.handle(qe)
 		}
 	})
 	return nil
 }
 
+func (el *ChanLoop) handle(qe queuedEvent) {
+	if el.dispatched != nil {
+		el.dispatched(time.Since(qe.sent))
+	}
+	qe.ev.Handle()
+}
+
+// stop handles the priority events that were sent before the stop but were not
+// selected ahead of it and marks the loop as stopped
+func (el *ChanLoop) stop() {
+	for {
+		select {
+		case qe := <-el.priority:
+			el.handle(qe)
+		default:
+			close(el.stopped)
+			return
+		}
+	}
+}
+
 // Send sends event to channel. Will block if buffer is full.
+// Returns ErrLoopClosed if the loop is draining.
 func (el *ChanLoop) Send(ev Event) error {
-	el.events <- ev
+	return el.send(el.events, ev)
+}
+
+// SendPriority sends event to the priority channel so that it is handled
+// before the events sent with Send. Will block if buffer is full.
+// Returns ErrLoopClosed if the loop is draining.
+func (el *ChanLoop) SendPriority(ev Event) error {
+	return el.send(el.priority, ev)
+}
+
+func (el *ChanLoop) send(ch chan queuedEvent, ev Event) error {
+	el.m.Lock()
+	if el.closed {
+		el.m.Unlock()
+		return ErrLoopClosed
+	}
+	el.senders.Add(1)
+	el.m.Unlock()
+	defer el.senders.Done()
+	ch <- queuedEvent{ev: ev, sent: time.Now()}
 	return nil
 }
+
+// Drain stops accepting new events, handles all the events already sent and
+// stops the loop.  It returns once the loop stopped or with the context's error
+// if the context is done first, in which case the loop still stops after the
+// queued events are handled.  Calling Drain again waits for the same stop.
+func (el *ChanLoop) Drain(ctx context.Context) error {
+	el.m.Lock()
+	if !el.closed {
+		el.closed = true
+		go func() {
+			// a send in progress is queued before the stop
+			el.senders.Wait()
+			el.events <- queuedEvent{stop: true}
+		}()
+	}
+	el.m.Unlock()
+	select {
+	case <-el.stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Stop drains the loop without a deadline.
+func (el *ChanLoop) Stop() error {
+	return el.Drain(context.Background())
+}
+
+// Len returns the number of events waiting to be handled.
+func (el *ChanLoop) Len() int {
+	return len(el.events) + len(el.priority)
+}
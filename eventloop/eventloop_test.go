@@ -4,6 +4,8 @@ import (
 	"sync"
 	"testing"
 	"time"
+
+	"golang.org/x/net/context"
 )
 
 type racyEvent struct {
@@ -71,3 +73,118 @@ func TestChanEventSpawn(t *testing.T) {
 		t.Fatal("Events was not handled in loop")
 	}
 }
+
+func TestChanObservedLen(t *testing.T) {
+	waits := make(chan time.Duration, 2)
+	e := NewObservedChanLoop(1024, func(wait time.Duration) {
+		waits <- wait
+	})
+	wg := &sync.WaitGroup{}
+	wg.Add(2)
+	e.Send(&testEvent{wg: wg})
+	e.Send(&testEvent{wg: wg})
+	if n := e.Len(); n != 2 {
+		t.Fatalf("expected 2 queued events but received %d", n)
+	}
+	e.Start()
+	wg.Wait()
+	for i := 0; i < 2; i++ {
+		select {
+		case w := <-waits:
+			if w < 0 {
+				t.Fatalf("expected a positive wait but received %s", w)
+			}
+		case <-time.After(1 * time.Second):
+			t.Fatal("dispatch was not observed")
+		}
+	}
+	if n := e.Len(); n != 0 {
+		t.Fatalf("expected an empty loop but received %d", n)
+	}
+}
+
+type orderEvent struct {
+	n     int
+	order chan int
+}
+
+func (e *orderEvent) Handle() {
+	e.order <- e.n
+}
+
+func TestChanPriority(t *testing.T) {
+	e := NewObservedChanLoop(1024, nil)
+	order := make(chan int, 6)
+	for i := 0; i < 3; i++ {
+		e.Send(&orderEvent{n: i + 3, order: order})
+		e.SendPriority(&orderEvent{n: i, order: order})
+	}
+	e.Start()
+	for i := 0; i < 6; i++ {
+		select {
+		case n := <-order:
+			if n != i {
+				t.Fatalf("expected event %d to be handled but received %d", i, n)
+			}
+		case <-time.After(1 * time.Second):
+			t.Fatal("Events was not handled in loop")
+		}
+	}
+}
+
+func TestChanDrain(t *testing.T) {
+	e := NewObservedChanLoop(1024, nil)
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 512; i++ {
+		wg.Add(2)
+		e.Send(&testEvent{wg: wg})
+		e.SendPriority(&testEvent{wg: wg})
+	}
+	e.Start()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := e.Drain(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if n := e.Len(); n != 0 {
+		t.Fatalf("expected all events to be handled but %d are queued", n)
+	}
+	// Drain returns after the events are handled so the wait group is done
+	waitCh := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(waitCh)
+	}()
+	select {
+	case <-waitCh:
+	case <-time.After(1 * time.Second):
+		t.Fatal("Events was not handled before the loop was drained")
+	}
+	if err := e.Send(&testEvent{wg: wg}); err != ErrLoopClosed {
+		t.Fatalf("expected ErrLoopClosed but received %v", err)
+	}
+	if err := e.SendPriority(&testEvent{wg: wg}); err != ErrLoopClosed {
+		t.Fatalf("expected ErrLoopClosed but received %v", err)
+	}
+	if err := e.Stop(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestChanDrainTimeout(t *testing.T) {
+	e := NewObservedChanLoop(1024, nil)
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	e.Send(&testEvent{wg: wg})
+	// the loop is not started so the queued event can not be handled
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := e.Drain(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("expected the drain to time out but received %v", err)
+	}
+	e.Start()
+	if err := e.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	wg.Wait()
+}
@@ -1,16 +1,24 @@
 package runtime
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/opencontainers/runc/libcontainer"
+	"github.com/opencontainers/runc/libcontainer/cgroups"
+	"github.com/opencontainers/runc/libcontainer/cgroups/fs"
 	"github.com/opencontainers/specs"
 )
 
@@ -20,7 +28,7 @@ type Container interface {
 	// Path returns the path to the bundle
 	Path() string
 	// Start starts the init process of the container
-	Start(checkpoint string, s Stdio) (Process, error)
+	Start(checkpoint *Checkpoint, s Stdio) (Process, error)
 	// Exec starts another process in an existing container
 	Exec(string, specs.Process, Stdio) (Process, error)
 	// Delete removes the container's state and any resources
@@ -29,24 +37,52 @@ type Container interface {
 	Processes() ([]Process, error)
 	// State returns the containers runtime state
 	State() State
-	// Resume resumes a paused container
-	Resume() error
-	// Pause pauses a running container
-	Pause() error
+	// Resume resumes a paused container, a zero timeout waits forever.  If the
+	// timeout is reached the runtime is killed, the container is thawed and
+	// ErrTimeout is returned.
+	Resume(timeout time.Duration) error
+	// Pause pauses a running container, a zero timeout waits forever.  If the
+	// timeout is reached the container is thawed and ErrTimeout is returned.
+	Pause(timeout time.Duration) error
 	// RemoveProcess removes the specified process from the container
 	RemoveProcess(string) error
-	// Checkpoints returns all the checkpoints for a container
-	Checkpoints() ([]Checkpoint, error)
+	// Checkpoints returns all the checkpoints stored in path, the container's
+	// checkpoints directory when path is empty, sorted by the time they were
+	// created.  It is empty if the directory does not exist.
+	Checkpoints(path string) ([]Checkpoint, error)
 	// Checkpoint creates a new checkpoint
-	Checkpoint(Checkpoint) error
-	// DeleteCheckpoint deletes the checkpoint for the provided name
-	DeleteCheckpoint(name string) error
+	Checkpoint(cpt Checkpoint, opts CheckpointOpts) error
+	// ReplaceCheckpoint creates a new checkpoint replacing any existing checkpoint
+	// with the same name.  The existing checkpoint is kept if the new one fails.
+	ReplaceCheckpoint(cpt Checkpoint, opts CheckpointOpts) error
+	// DeleteCheckpoint deletes the checkpoint for the provided name from path, the
+	// container's checkpoints directory when path is empty
+	DeleteCheckpoint(name, path string) error
 	// Labels are user provided labels for the container
 	Labels() []string
+	// Spec returns the OCI spec from the container's bundle
+	Spec() (*specs.LinuxSpec, error)
+	// PidsCurrent returns the number of tasks in the container's pids cgroup
+	PidsCurrent() (int64, error)
+	// PidsLimit returns the maximum number of tasks in the container's pids cgroup,
+	// -1 if there is no limit
+	PidsLimit() (int64, error)
+	// Resources returns the current resource limits of the container's cgroups
+	Resources() (Resources, error)
+	// UpdateResources applies the changed resource limits to the container's cgroups
+	UpdateResources(r Resources) error
+	// Hostname returns the hostname of the container's UTS namespace
+	Hostname() (string, error)
+	// SetHostname sets the hostname of the container's UTS namespace
+	SetHostname(name string) error
 	// Pids returns all pids inside the container
 	Pids() ([]int, error)
 	// Stats returns realtime container stats and resource information
 	Stats() (*Stat, error)
+	// MemoryPressure signals the channel when the container's memory cgroup reaches
+	// the pressure level, low, medium or critical, the channel is closed when the
+	// cgroup is removed
+	MemoryPressure(level string) (<-chan struct{}, error)
 	// OOM signals the channel if the container received an OOM notification
 	// OOM() (<-chan struct{}, error)
 }
@@ -72,32 +108,63 @@ func NewStdio(stdin, stdout, stderr string) Stdio {
 	}
 }
 
-// New returns a new container
-func New(root, id, bundle string, labels []string) (Container, error) {
+// New returns a new container.  runtime is the OCI runtime binary that runs
+// the container, DefaultRuntime if it is empty, and runtimeArgs are passed to
+// the runtime before its command for all invocations for the container.  If initWrapper
+// is not empty it is the path of a binary that is injected into the container
+// to run as its init process, reaping zombies and forwarding signals to the
+// process in the container's spec.
+func New(root, id, bundle string, labels []string, runtime string, runtimeArgs []string, initWrapper string) (Container, error) {
 	c := &container{
-		root:      root,
-		id:        id,
-		bundle:    bundle,
-		labels:    labels,
-		processes: make(map[string]*process),
+		root:        root,
+		id:          id,
+		bundle:      bundle,
+		labels:      labels,
+		runtime:     runtime,
+		runtimeArgs: runtimeArgs,
+		initWrapper: initWrapper,
+		processes:   make(map[string]*process),
+		driver:      NewDriver(runtime, runtimeArgs),
 	}
 	if err := os.Mkdir(filepath.Join(root, id), 0755); err != nil {
 		return nil, err
 	}
-	f, err := os.Create(filepath.Join(root, id, StateFile))
-	if err != nil {
-		return nil, err
-	}
-	defer f.Close()
-	if err := json.NewEncoder(f).Encode(state{
-		Bundle: bundle,
-		Labels: labels,
+	if err := writeStateFile(filepath.Join(root, id, StateFile), state{
+		Bundle:      bundle,
+		Labels:      labels,
+		RuntimeArgs: runtimeArgs,
+		InitWrapper: initWrapper,
+		Runtime:     runtime,
 	}); err != nil {
+		// a container without its state file cannot be loaded
+		os.RemoveAll(filepath.Join(root, id))
 		return nil, err
 	}
 	return c, nil
 }
 
+// writeStateFile writes the json encoded state to a temporary file that is renamed
+// to path so that a failed write, such as ENOSPC, never leaves a truncated state
+// file.  The error of the failed write is returned.
+func writeStateFile(path string, v interface{}) error {
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	err = json.NewEncoder(f).Encode(v)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		os.Remove(tmp)
+	}
+	return err
+}
+
 func Load(root, id string) (Container, error) {
 	var s state
 	f, err := os.Open(filepath.Join(root, id, StateFile))
@@ -109,18 +176,22 @@ func Load(root, id string) (Container, error) {
 		return nil, err
 	}
 	c := &container{
-		root:      root,
-		id:        id,
-		bundle:    s.Bundle,
-		labels:    s.Labels,
-		processes: make(map[string]*process),
+		root:        root,
+		id:          id,
+		bundle:      s.Bundle,
+		labels:      s.Labels,
+		runtime:     s.Runtime,
+		runtimeArgs: s.RuntimeArgs,
+		initWrapper: s.InitWrapper,
+		processes:   make(map[string]*process),
+		driver:      NewDriver(s.Runtime, s.RuntimeArgs),
 	}
 	dirs, err := ioutil.ReadDir(filepath.Join(root, id))
 	if err != nil {
 		return nil, err
 	}
 	for _, d := range dirs {
-		if !d.IsDir() {
+		if !d.IsDir() || d.Name() == InitBundleDir {
 			continue
 		}
 		pid := d.Name()
@@ -153,12 +224,23 @@ func readProcessState(dir string) (*ProcessState, error) {
 
 type container struct {
 	// path to store runtime state information
-	root      string
-	id        string
-	bundle    string
-	processes map[string]*process
-	stdio     Stdio
-	labels    []string
+	root   string
+	id     string
+	bundle string
+	// processesLock guards processes which is changed by execs that are
+	// started concurrently
+	processesLock sync.RWMutex
+	processes     map[string]*process
+	stdio         Stdio
+	labels        []string
+	// runtime is the OCI runtime binary, empty for DefaultRuntime, and runtimeArgs
+	// are passed to the runtime before its command
+	runtime     string
+	runtimeArgs []string
+	// driver runs the container's runtime operations
+	driver RuntimeDriver
+	// initWrapper is the path of the binary run as the container's init process
+	initWrapper string
 }
 
 func (c *container) ID() string {
@@ -173,22 +255,30 @@ func (c *container) Labels() []string {
 	return c.labels
 }
 
-func (c *container) Start(checkpoint string, s Stdio) (Process, error) {
+func (c *container) Start(checkpoint *Checkpoint, s Stdio) (Process, error) {
 	processRoot := filepath.Join(c.root, c.id, InitProcessID)
 	if err := os.Mkdir(processRoot, 0755); err != nil {
 		return nil, err
 	}
+	spec, err := c.readSpec()
+	if err != nil {
+		return nil, err
+	}
+	bundle := c.bundle
+	// a restored checkpoint already contains the container's init process
+	if c.initWrapper != "" && checkpoint == nil {
+		spec = c.wrapInit(spec)
+		if bundle, err = c.writeInitBundle(spec); err != nil {
+			return nil, err
+		}
+	}
 	cmd := exec.Command("containerd-shim",
-		c.id, c.bundle,
+		c.id, bundle,
 	)
 	cmd.Dir = processRoot
 	cmd.SysProcAttr = &syscall.SysProcAttr{
 		Setpgid: true,
 	}
-	spec, err := c.readSpec()
-	if err != nil {
-		return nil, err
-	}
 	config := &processConfig{
 		checkpoint:  checkpoint,
 		root:        processRoot,
@@ -208,7 +298,9 @@ func (c *container) Start(checkpoint string, s Stdio) (Process, error) {
 	if _, err := p.getPid(); err != nil {
 		return p, nil
 	}
+	c.processesLock.Lock()
 	c.processes[InitProcessID] = p
+	c.processesLock.Unlock()
 	return p, nil
 }
 
@@ -224,6 +316,15 @@ func (c *container) Exec(pid string, spec specs.Process, s Stdio) (Process, erro
 	cmd.SysProcAttr = &syscall.SysProcAttr{
 		Setpgid: true,
 	}
+	// the shim logs why the runtime failed to start the process, output that is
+	// not logged such as a panic goes to the same file
+	shimLog, err := os.OpenFile(filepath.Join(processRoot, ShimLogFile), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		os.RemoveAll(processRoot)
+		return nil, err
+	}
+	defer shimLog.Close()
+	cmd.Stderr = shimLog
 	config := &processConfig{
 		exec:        true,
 		id:          pid,
@@ -234,18 +335,43 @@ func (c *container) Exec(pid string, spec specs.Process, s Stdio) (Process, erro
 	}
 	p, err := newProcess(config)
 	if err != nil {
+		os.RemoveAll(processRoot)
 		return nil, err
 	}
 	if err := cmd.Start(); err != nil {
+		p.closePipes()
+		os.RemoveAll(processRoot)
 		return nil, err
 	}
 	if _, err := p.getPid(); err != nil {
-		return p, nil
+		// the shim may still be waiting on the runtime
+		cmd.Process.Kill()
+		cmd.Wait()
+		err = shimError(processRoot, err)
+		p.closePipes()
+		os.RemoveAll(processRoot)
+		return nil, err
 	}
+	c.processesLock.Lock()
 	c.processes[pid] = p
+	c.processesLock.Unlock()
 	return p, nil
 }
 
+// shimError returns the error logged by the shim that failed to start the process
+// in processRoot or err if the shim did not log anything
+func shimError(processRoot string, err error) error {
+	data, rerr := ioutil.ReadFile(filepath.Join(processRoot, ShimLogFile))
+	if rerr != nil || len(bytes.TrimSpace(data)) == 0 {
+		return err
+	}
+	return fmt.Errorf("containerd: start process: %s", bytes.TrimSpace(data))
+}
+
+func (c *container) Spec() (*specs.LinuxSpec, error) {
+	return c.readSpec()
+}
+
 func (c *container) readSpec() (*specs.LinuxSpec, error) {
 	var spec specs.LinuxSpec
 	f, err := os.Open(filepath.Join(c.bundle, "config.json"))
@@ -259,15 +385,108 @@ func (c *container) readSpec() (*specs.LinuxSpec, error) {
 	return &spec, nil
 }
 
-func (c *container) Pause() error {
-	return exec.Command("runc", "pause", c.id).Run()
+func (c *container) Pause(timeout time.Duration) error {
+	err := c.driver.Pause(c.id, timeout)
+	if err == ErrTimeout {
+		// freezing can hang on tasks in uninterruptible sleep so put the cgroup
+		// back into a consistent state
+		if terr := c.thaw(); terr != nil {
+			logrus.WithFields(logrus.Fields{
+				"id":    c.id,
+				"error": terr,
+			}).Error("containerd: thaw container after pause timeout")
+		}
+	}
+	return err
 }
 
-func (c *container) Resume() error {
-	return exec.Command("runc", "resume", c.id).Run()
+func (c *container) Resume(timeout time.Duration) error {
+	err := c.driver.Resume(c.id, timeout)
+	if err == ErrTimeout {
+		// the runtime was killed so finish thawing the cgroup
+		if terr := c.thaw(); terr != nil {
+			logrus.WithFields(logrus.Fields{
+				"id":    c.id,
+				"error": terr,
+			}).Error("containerd: thaw container after resume timeout")
+		}
+	}
+	return err
+}
+
+// thaw writes directly to the container's freezer cgroup to thaw it
+func (c *container) thaw() error {
+	paths, err := c.driver.CgroupPaths(c.id)
+	if err != nil {
+		return err
+	}
+	path, ok := paths["freezer"]
+	if !ok {
+		return ErrFreezerNotSupported
+	}
+	return ioutil.WriteFile(filepath.Join(path, "freezer.state"), []byte("THAWED"), 0)
+}
+
+// runUntil runs the command and kills it if cancel is closed before it exits
+func runUntil(cmd *exec.Cmd, cancel <-chan struct{}) error {
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-cancel:
+		cmd.Process.Kill()
+		<-done
+		return ErrCanceled
+	}
+}
+
+// runWithTimeout runs the command and kills it if it has not exited after timeout
+func runWithTimeout(cmd *exec.Cmd, timeout time.Duration) error {
+	if timeout <= 0 {
+		return cmd.Run()
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(timeout):
+		cmd.Process.Kill()
+		<-done
+		return ErrTimeout
+	}
+}
+
+// initProcess returns the container's init process if it has been started
+func (c *container) initProcess() (*process, bool) {
+	c.processesLock.RLock()
+	defer c.processesLock.RUnlock()
+	p, ok := c.processes[InitProcessID]
+	return p, ok
 }
 
 func (c *container) State() State {
+	p, ok := c.initProcess()
+	if !ok {
+		return Created
+	}
+	if _, err := p.ExitStatus(); err == nil {
+		return Stopped
+	}
+	if paused, err := c.driver.Paused(c.id); err == nil && paused {
+		return Paused
+	}
 	return Running
 }
 
@@ -276,6 +495,8 @@ func (c *container) Delete() error {
 }
 
 func (c *container) Processes() ([]Process, error) {
+	c.processesLock.RLock()
+	defer c.processesLock.RUnlock()
 	out := []Process{}
 	for _, p := range c.processes {
 		out = append(out, p)
@@ -284,22 +505,28 @@ func (c *container) Processes() ([]Process, error) {
 }
 
 func (c *container) RemoveProcess(pid string) error {
+	c.processesLock.Lock()
 	delete(c.processes, pid)
+	c.processesLock.Unlock()
 	return os.RemoveAll(filepath.Join(c.root, c.id, pid))
 }
 
-func (c *container) Checkpoints() ([]Checkpoint, error) {
-	dirs, err := ioutil.ReadDir(filepath.Join(c.bundle, "checkpoints"))
+func (c *container) Checkpoints(path string) ([]Checkpoint, error) {
+	root := c.checkpointsDir(path)
+	dirs, err := ioutil.ReadDir(root)
 	if err != nil {
+		if os.IsNotExist(err) {
+			return []Checkpoint{}, nil
+		}
 		return nil, err
 	}
-	var out []Checkpoint
+	out := []Checkpoint{}
 	for _, d := range dirs {
-		if !d.IsDir() {
+		// skip checkpoints that are being created or replaced
+		if !d.IsDir() || strings.HasPrefix(d.Name(), ".") {
 			continue
 		}
-		path := filepath.Join(c.bundle, "checkpoints", d.Name(), "config.json")
-		data, err := ioutil.ReadFile(path)
+		data, err := ioutil.ReadFile(filepath.Join(root, d.Name(), "config.json"))
 		if err != nil {
 			return nil, err
 		}
@@ -307,19 +534,109 @@ func (c *container) Checkpoints() ([]Checkpoint, error) {
 		if err := json.Unmarshal(data, &cpt); err != nil {
 			return nil, err
 		}
+		cpt.Path = path
 		out = append(out, cpt)
 	}
+	sort.Sort(checkpointsByCreated(out))
 	return out, nil
 }
 
-func (c *container) Checkpoint(cpt Checkpoint) error {
-	if err := os.MkdirAll(filepath.Join(c.bundle, "checkpoints"), 0755); err != nil {
+// LoadCheckpoint returns the checkpoint with the provided name stored in dir or
+// ErrCheckpointNotExists if the checkpoint does not exist
+func LoadCheckpoint(dir, name string) (*Checkpoint, error) {
+	data, err := ioutil.ReadFile(filepath.Join(dir, name, "config.json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrCheckpointNotExists
+		}
+		return nil, err
+	}
+	var cpt Checkpoint
+	if err := json.Unmarshal(data, &cpt); err != nil {
+		return nil, err
+	}
+	return &cpt, nil
+}
+
+type checkpointsByCreated []Checkpoint
+
+func (c checkpointsByCreated) Len() int           { return len(c) }
+func (c checkpointsByCreated) Less(i, j int) bool { return c[i].Created.Before(c[j].Created) }
+func (c checkpointsByCreated) Swap(i, j int)      { c[i], c[j] = c[j], c[i] }
+
+// checkpointsDir returns the directory that checkpoints with the provided path
+// are stored in
+func (c *container) checkpointsDir(path string) string {
+	if path != "" {
+		return path
+	}
+	return filepath.Join(c.bundle, "checkpoints")
+}
+
+// Checkpoint writes the checkpoint to a temporary directory that is renamed once
+// the checkpoint is complete so that a partial checkpoint is never visible under
+// its name.
+func (c *container) Checkpoint(cpt Checkpoint, opts CheckpointOpts) error {
+	root := c.checkpointsDir(cpt.Path)
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return err
+	}
+	path := filepath.Join(root, cpt.Name)
+	if _, err := os.Lstat(path); err == nil {
+		return ErrCheckpointExists
+	}
+	tmp, err := ioutil.TempDir(root, "."+cpt.Name)
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+	if err := c.checkpoint(tmp, cpt, opts); err != nil {
+		return err
+	}
+	opts.progress(CheckpointPhaseCommit)
+	if err := os.Rename(tmp, path); err != nil {
+		// a checkpoint with the same name was created during the dump
+		if le, ok := err.(*os.LinkError); ok && (le.Err == syscall.EEXIST || le.Err == syscall.ENOTEMPTY) {
+			return ErrCheckpointExists
+		}
+		return err
+	}
+	return nil
+}
+
+func (c *container) ReplaceCheckpoint(cpt Checkpoint, opts CheckpointOpts) error {
+	root := c.checkpointsDir(cpt.Path)
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempDir(root, "."+cpt.Name)
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+	if err := c.checkpoint(tmp, cpt, opts); err != nil {
+		return err
+	}
+	opts.progress(CheckpointPhaseCommit)
+	var (
+		path = filepath.Join(root, cpt.Name)
+		old  = tmp + ".old"
+	)
+	// move the existing checkpoint aside so that it can be put back if the new
+	// checkpoint cannot be moved into place
+	if err := os.Rename(path, old); err != nil && !os.IsNotExist(err) {
 		return err
 	}
-	path := filepath.Join(c.bundle, "checkpoints", cpt.Name)
-	if err := os.Mkdir(path, 0755); err != nil {
+	if err := os.Rename(tmp, path); err != nil {
+		os.Rename(old, path)
 		return err
 	}
+	return os.RemoveAll(old)
+}
+
+// checkpoint writes the checkpoint's config and the runtime's image to path
+func (c *container) checkpoint(path string, cpt Checkpoint, opts CheckpointOpts) error {
+	opts.progress(CheckpointPhasePrepare)
 	f, err := os.Create(filepath.Join(path, "config.json"))
 	if err != nil {
 		return err
@@ -330,63 +647,133 @@ func (c *container) Checkpoint(cpt Checkpoint) error {
 	if err != nil {
 		return err
 	}
-	args := []string{
-		"checkpoint",
-		"--image-path", path,
+	opts.progress(CheckpointPhaseDump)
+	err = runUntil(c.driver.Checkpoint(c.id, path, cpt), opts.Cancel)
+	if err == ErrCanceled {
+		// the container is frozen while it is dumped so put the cgroup back into a
+		// consistent state
+		if terr := c.thaw(); terr != nil {
+			logrus.WithFields(logrus.Fields{
+				"id":    c.id,
+				"error": terr,
+			}).Error("containerd: thaw container after cancelled checkpoint")
+		}
 	}
-	add := func(flags ...string) {
-		args = append(args, flags...)
+	return err
+}
+
+func (c *container) DeleteCheckpoint(name, path string) error {
+	return os.RemoveAll(filepath.Join(c.checkpointsDir(path), name))
+}
+
+// pidsCgroup returns the path of the container's pids cgroup
+func (c *container) pidsCgroup() (string, error) {
+	paths, err := c.driver.CgroupPaths(c.id)
+	if err != nil {
+		return "", err
 	}
-	if !cpt.Exit {
-		add("--leave-running")
+	path, ok := paths["pids"]
+	if !ok || !cgroups.PathExists(path) {
+		return "", ErrPidsNotSupported
 	}
-	if cpt.Shell {
-		add("--shell-job")
+	return path, nil
+}
+
+func (c *container) PidsLimit() (int64, error) {
+	path, err := c.pidsCgroup()
+	if err != nil {
+		return 0, err
 	}
-	if cpt.Tcp {
-		add("--tcp-established")
+	data, err := ioutil.ReadFile(filepath.Join(path, "pids.max"))
+	if err != nil {
+		return 0, err
 	}
-	if cpt.UnixSockets {
-		add("--ext-unix-sk")
+	max := strings.TrimSpace(string(data))
+	if max == "max" {
+		return -1, nil
 	}
-	add(c.id)
-	return exec.Command("runc", args...).Run()
+	return strconv.ParseInt(max, 10, 64)
 }
 
-func (c *container) DeleteCheckpoint(name string) error {
-	return os.RemoveAll(filepath.Join(c.bundle, "checkpoints", name))
+func (c *container) PidsCurrent() (int64, error) {
+	path, err := c.pidsCgroup()
+	if err != nil {
+		return 0, err
+	}
+	data, err := ioutil.ReadFile(filepath.Join(path, "pids.current"))
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
 }
 
 func (c *container) Pids() ([]int, error) {
-	container, err := c.getLibctContainer()
-	if err != nil {
-		return nil, err
-	}
-	return container.Processes()
+	return c.driver.Processes(c.id)
 }
 
 func (c *container) Stats() (*Stat, error) {
-	container, err := c.getLibctContainer()
+	// the network namespace is gone once init exits so there is nothing to collect
+	// interface stats from
+	init, ok := c.initProcess()
+	if !ok {
+		return nil, ErrContainerNotStarted
+	}
+	if _, err := init.ExitStatus(); err == nil {
+		return nil, ErrContainerExited
+	}
+	now := time.Now()
+	paths, err := c.driver.CgroupPaths(c.id)
 	if err != nil {
 		return nil, err
 	}
-	now := time.Now()
-	stats, err := container.Stats()
+	available := make(map[string]bool, len(statsGroups))
+	stats, err := c.driver.Stats(c.id)
+	if err != nil {
+		// collect what we can from the controllers individually
+		logrus.WithFields(logrus.Fields{
+			"id":    c.id,
+			"error": err,
+		}).Debug("containerd: collecting partial stats")
+		stats = &libcontainer.Stats{
+			CgroupStats: cgroups.NewStats(),
+		}
+		for name, g := range statsGroups {
+			path, ok := paths[name]
+			available[name] = ok && cgroups.PathExists(path) && g.GetStats(path, stats.CgroupStats) == nil
+		}
+	} else {
+		for name := range statsGroups {
+			path, ok := paths[name]
+			available[name] = ok && cgroups.PathExists(path)
+		}
+	}
+	data := newCgroupStats(stats)
+	network, err := readNetDev(init.SystemPid())
 	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrContainerExited
+		}
 		return nil, err
 	}
+	data.Network = network
 	return &Stat{
 		Timestamp: now,
-		Data:      stats,
+		Data:      data,
+		Raw:       stats,
+		Available: available,
 	}, nil
 }
 
-func (c *container) getLibctContainer() (libcontainer.Container, error) {
-	f, err := libcontainer.New(specs.LinuxStateDirectory, libcontainer.Cgroupfs)
-	if err != nil {
-		return nil, err
-	}
-	return f.Load(c.id)
+// statsGroups are the cgroup controllers that provide container stats
+var statsGroups = map[string]interface {
+	GetStats(path string, stats *cgroups.Stats) error
+}{
+	"cpu":     &fs.CpuGroup{},
+	"cpuacct": &fs.CpuacctGroup{},
+	"memory":  &fs.MemoryGroup{},
+	"blkio":   &fs.BlkioGroup{},
+	"hugetlb": &fs.HugetlbGroup{},
+	"pids":    &fs.PidsGroup{},
 }
 
 func getRootIDs(s *specs.LinuxSpec) (int, int, error) {
@@ -0,0 +1,148 @@
+package runtime
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/opencontainers/specs"
+)
+
+func TestExecReturnsShimError(t *testing.T) {
+	dir, err := ioutil.TempDir("", "containerd-runtime")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	// the shim reports the failure and then hangs so that exec has to kill it
+	bin := filepath.Join(dir, "bin")
+	if err := os.Mkdir(bin, 0755); err != nil {
+		t.Fatal(err)
+	}
+	shim := "#!/bin/sh\necho \"runtime failed\" >&2\nexec sleep 30\n"
+	if err := ioutil.WriteFile(filepath.Join(bin, "containerd-shim"), []byte(shim), 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := os.Getenv("PATH")
+	defer os.Setenv("PATH", path)
+	os.Setenv("PATH", bin+string(os.PathListSeparator)+path)
+
+	c, err := New(dir, "test", filepath.Join(dir, "bundle"), nil, "", nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = c.Exec("exec", specs.Process{}, Stdio{})
+	if err == nil {
+		t.Fatal("expected exec to fail")
+	}
+	if !strings.Contains(err.Error(), "runtime failed") {
+		t.Fatalf("expected the shim's error but received %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "test", "exec")); !os.IsNotExist(err) {
+		t.Fatalf("expected the process state directory to be removed but received %v", err)
+	}
+}
+
+func TestExecRemovesProcessRootWhenShimMissing(t *testing.T) {
+	dir, err := ioutil.TempDir("", "containerd-runtime")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := os.Getenv("PATH")
+	defer os.Setenv("PATH", path)
+	os.Setenv("PATH", filepath.Join(dir, "bin"))
+
+	c, err := New(dir, "test", filepath.Join(dir, "bundle"), nil, "", nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Exec("exec", specs.Process{}, Stdio{}); err == nil {
+		t.Fatal("expected exec to fail without a shim")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "test", "exec")); !os.IsNotExist(err) {
+		t.Fatalf("expected the process state directory to be removed but received %v", err)
+	}
+}
+
+func TestRunUntilKillsCancelledCommand(t *testing.T) {
+	cancel := make(chan struct{})
+	close(cancel)
+	start := time.Now()
+	if err := runUntil(exec.Command("sleep", "10"), cancel); err != ErrCanceled {
+		t.Fatalf("expected %v but received %v", ErrCanceled, err)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Fatal("expected the command to be killed")
+	}
+	if err := runUntil(exec.Command("true"), make(chan struct{})); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCheckpointReportsPhases(t *testing.T) {
+	dir, err := ioutil.TempDir("", "containerd-runtime")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	binary := filepath.Join(dir, "runtime")
+	if err := ioutil.WriteFile(binary, []byte("#!/bin/sh\nexit 0\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	c, err := New(dir, "test", filepath.Join(dir, "bundle"), nil, binary, nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var phases []string
+	if err := c.Checkpoint(Checkpoint{Name: "cpt"}, CheckpointOpts{
+		Progress: func(phase string) {
+			phases = append(phases, phase)
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{CheckpointPhasePrepare, CheckpointPhaseDump, CheckpointPhaseCommit}
+	if !reflect.DeepEqual(phases, expected) {
+		t.Fatalf("expected the phases %v but received %v", expected, phases)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "bundle", "checkpoints", "cpt", "config.json")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWriteStateFileNoSpace(t *testing.T) {
+	dir, err := ioutil.TempDir("", "containerd-runtime")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, StateFile)
+	if err := ioutil.WriteFile(path, []byte(`{"bundle":"/bundle"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// writes to /dev/full fail with ENOSPC
+	if err := os.Symlink("/dev/full", path+".tmp"); err != nil {
+		t.Fatal(err)
+	}
+	err = writeStateFile(path, state{Bundle: "/other"})
+	if perr, ok := err.(*os.PathError); !ok || perr.Err != syscall.ENOSPC {
+		t.Fatalf("expected ENOSPC but received %v", err)
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"bundle":"/bundle"}` {
+		t.Fatalf("expected the state file to be unchanged but received %q", data)
+	}
+	if _, err := os.Lstat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Fatalf("expected the temporary file to be removed but received %v", err)
+	}
+}
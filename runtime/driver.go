@@ -0,0 +1,353 @@
+package runtime
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/opencontainers/runc/libcontainer"
+	"github.com/opencontainers/runc/libcontainer/cgroups"
+	"github.com/opencontainers/specs"
+)
+
+// RuntimeDriver runs containers with an OCI runtime.  The shim runs the commands
+// returned by Create and Exec so that it is the parent of the container's
+// processes, the daemon calls the other methods.
+type RuntimeDriver interface {
+	// Create returns the command that creates the container from its bundle, or
+	// restores it from a checkpoint, and runs its init process in the background
+	Create(id string, opts CreateOpts) *exec.Cmd
+	// Start starts the init process once the create command has exited, it does
+	// nothing for runtimes whose create command starts the process
+	Start(id string) error
+	// Exec returns the command that runs an additional process in the container
+	// in the background
+	Exec(id string, opts ExecOpts) *exec.Cmd
+	// Delete removes the runtime's state for the container
+	Delete(id string) error
+	// Pause freezes the processes of the container, the runtime is killed if it
+	// has not returned after timeout
+	Pause(id string, timeout time.Duration) error
+	// Resume thaws the processes of the container, the runtime is killed if it
+	// has not returned after timeout
+	Resume(id string, timeout time.Duration) error
+	// Checkpoint returns the command that writes the runtime's image of the
+	// container to path
+	Checkpoint(id, path string, cpt Checkpoint) *exec.Cmd
+	// Paused returns true if the container is paused or being paused
+	Paused(id string) (bool, error)
+	// CgroupPaths returns the paths of the container's cgroups by subsystem
+	CgroupPaths(id string) (map[string]string, error)
+	// Processes returns the pids of the processes in the container
+	Processes(id string) ([]int, error)
+	// Stats returns the stats of the container's cgroups
+	Stats(id string) (*libcontainer.Stats, error)
+	// MemoryPressure returns a channel that receives when the container's memory
+	// cgroup reaches level
+	MemoryPressure(id string, level libcontainer.PressureLevel) (<-chan struct{}, error)
+}
+
+// CreateOpts are the options for creating a container's init process
+type CreateOpts struct {
+	Bundle  string
+	Console string
+	// PidFile is where the runtime writes the pid of the init process
+	PidFile string
+	// Checkpoint is restored instead of starting the process in the bundle
+	Checkpoint *Checkpoint
+}
+
+// ExecOpts are the options for running an additional process in a container
+type ExecOpts struct {
+	// Process is the path of the process's spec
+	Process string
+	Console string
+	PidFile string
+}
+
+// stateCacheTimeout is how long the state reported by a runtime's state command
+// is used before the runtime is queried again
+const stateCacheTimeout = 1 * time.Second
+
+// NewDriver returns the driver for the OCI runtime binary, runtimeArgs are passed
+// to the runtime before its command.  An empty runtime or a path to a binary named
+// DefaultRuntime selects runc whose state is read with libcontainer, other
+// runtimes are queried with their state command and the cgroups of the
+// container's init process.
+func NewDriver(runtime string, runtimeArgs []string) RuntimeDriver {
+	if runtime == "" {
+		runtime = DefaultRuntime
+	}
+	d := &ociDriver{
+		binary: runtime,
+		args:   runtimeArgs,
+		states: make(map[string]cachedState),
+	}
+	if filepath.Base(runtime) == DefaultRuntime {
+		return &runcDriver{ociDriver: d}
+	}
+	return d
+}
+
+// ociDriver runs any runtime that implements the OCI runtime command line
+type ociDriver struct {
+	binary string
+	args   []string
+	// states caches the output of the state command by container so that
+	// frequent queries such as Paused and CgroupPaths do not each run the runtime
+	stateLock sync.Mutex
+	states    map[string]cachedState
+}
+
+type cachedState struct {
+	state   *ociState
+	expires time.Time
+}
+
+func (d *ociDriver) command(args ...string) *exec.Cmd {
+	return exec.Command(d.binary, append(append([]string{}, d.args...), args...)...)
+}
+
+func (d *ociDriver) Create(id string, opts CreateOpts) *exec.Cmd {
+	var args []string
+	if cpt := opts.Checkpoint; cpt != nil {
+		args = append(args, "restore",
+			"--image-path", filepath.Join(cpt.Path, cpt.Name),
+		)
+		args = append(args, checkpointFlags(*cpt)...)
+	} else {
+		args = append(args, "start",
+			"--bundle", opts.Bundle,
+			"--console", opts.Console,
+		)
+	}
+	args = append(args,
+		"-d",
+		"--pid-file", opts.PidFile,
+		id,
+	)
+	return d.command(args...)
+}
+
+func (d *ociDriver) Start(id string) error {
+	return nil
+}
+
+func (d *ociDriver) Exec(id string, opts ExecOpts) *exec.Cmd {
+	return d.command("exec",
+		"--process", opts.Process,
+		"--console", opts.Console,
+		"-d",
+		"--pid-file", opts.PidFile,
+		id,
+	)
+}
+
+func (d *ociDriver) Delete(id string) error {
+	defer d.forgetState(id)
+	return d.command("delete", id).Run()
+}
+
+func (d *ociDriver) Pause(id string, timeout time.Duration) error {
+	defer d.forgetState(id)
+	return runWithTimeout(d.command("pause", id), timeout)
+}
+
+func (d *ociDriver) Resume(id string, timeout time.Duration) error {
+	defer d.forgetState(id)
+	return runWithTimeout(d.command("resume", id), timeout)
+}
+
+func (d *ociDriver) Checkpoint(id, path string, cpt Checkpoint) *exec.Cmd {
+	args := []string{
+		"checkpoint",
+		"--image-path", path,
+	}
+	if !cpt.Exit {
+		args = append(args, "--leave-running")
+	}
+	args = append(args, checkpointFlags(cpt)...)
+	return d.command(append(args, id)...)
+}
+
+// checkpointFlags returns the flags for the checkpoint's options that are
+// shared by checkpoint and restore
+func checkpointFlags(cpt Checkpoint) []string {
+	var flags []string
+	if cpt.Shell {
+		flags = append(flags, "--shell-job")
+	}
+	if cpt.Tcp {
+		flags = append(flags, "--tcp-established")
+	}
+	if cpt.UnixSockets {
+		flags = append(flags, "--ext-unix-sk")
+	}
+	return flags
+}
+
+// ociState is the state of a container as reported by the runtime's state command
+type ociState struct {
+	Pid    int    `json:"pid"`
+	Status string `json:"status"`
+}
+
+// state returns the state of the container, the runtime is only queried if the
+// cached state is older than stateCacheTimeout
+func (d *ociDriver) state(id string) (*ociState, error) {
+	d.stateLock.Lock()
+	cached, ok := d.states[id]
+	d.stateLock.Unlock()
+	if ok && time.Now().Before(cached.expires) {
+		return cached.state, nil
+	}
+	data, err := d.command("state", id).Output()
+	if err != nil {
+		return nil, err
+	}
+	var s ociState
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	d.stateLock.Lock()
+	d.states[id] = cachedState{
+		state:   &s,
+		expires: time.Now().Add(stateCacheTimeout),
+	}
+	d.stateLock.Unlock()
+	return &s, nil
+}
+
+// forgetState removes the cached state of a container whose status was changed
+func (d *ociDriver) forgetState(id string) {
+	d.stateLock.Lock()
+	delete(d.states, id)
+	d.stateLock.Unlock()
+}
+
+func (d *ociDriver) Paused(id string) (bool, error) {
+	s, err := d.state(id)
+	if err != nil {
+		return false, err
+	}
+	return s.Status == "paused" || s.Status == "pausing", nil
+}
+
+// CgroupPaths returns the cgroups of the container's init process on the
+// cgroup mounts of the daemon
+func (d *ociDriver) CgroupPaths(id string) (map[string]string, error) {
+	s, err := d.state(id)
+	if err != nil {
+		return nil, err
+	}
+	dirs, err := cgroups.ParseCgroupFile(fmt.Sprintf("/proc/%d/cgroup", s.Pid))
+	if err != nil {
+		return nil, err
+	}
+	mounts, err := cgroups.GetCgroupMounts()
+	if err != nil {
+		return nil, err
+	}
+	paths := make(map[string]string)
+	for _, m := range mounts {
+		dir, err := m.GetThisCgroupDir(dirs)
+		if err != nil {
+			continue
+		}
+		rel, err := filepath.Rel(m.Root, dir)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		for _, subsystem := range m.Subsystems {
+			paths[subsystem] = filepath.Join(m.Mountpoint, rel)
+		}
+	}
+	return paths, nil
+}
+
+func (d *ociDriver) Processes(id string) ([]int, error) {
+	paths, err := d.CgroupPaths(id)
+	if err != nil {
+		return nil, err
+	}
+	path, ok := paths["devices"]
+	if !ok || !cgroups.PathExists(path) {
+		return nil, ErrCgroupNotSupported
+	}
+	return cgroups.GetPids(path)
+}
+
+// Stats is not implemented so that the stats are collected from the
+// container's cgroups individually
+func (d *ociDriver) Stats(id string) (*libcontainer.Stats, error) {
+	return nil, errNotImplemented
+}
+
+func (d *ociDriver) MemoryPressure(id string, level libcontainer.PressureLevel) (<-chan struct{}, error) {
+	return nil, errNotImplemented
+}
+
+// runcDriver reads the state that runc keeps for the container with libcontainer
+type runcDriver struct {
+	*ociDriver
+}
+
+func (d *runcDriver) container(id string) (libcontainer.Container, error) {
+	f, err := libcontainer.New(specs.LinuxStateDirectory, libcontainer.Cgroupfs)
+	if err != nil {
+		return nil, err
+	}
+	return f.Load(id)
+}
+
+func (d *runcDriver) Paused(id string) (bool, error) {
+	container, err := d.container(id)
+	if err != nil {
+		return false, err
+	}
+	status, err := container.Status()
+	if err != nil {
+		return false, err
+	}
+	return status == libcontainer.Paused || status == libcontainer.Pausing, nil
+}
+
+func (d *runcDriver) CgroupPaths(id string) (map[string]string, error) {
+	container, err := d.container(id)
+	if err != nil {
+		return nil, err
+	}
+	state, err := container.State()
+	if err != nil {
+		return nil, err
+	}
+	return state.CgroupPaths, nil
+}
+
+func (d *runcDriver) Processes(id string) ([]int, error) {
+	container, err := d.container(id)
+	if err != nil {
+		return nil, err
+	}
+	return container.Processes()
+}
+
+func (d *runcDriver) Stats(id string) (*libcontainer.Stats, error) {
+	container, err := d.container(id)
+	if err != nil {
+		return nil, err
+	}
+	return container.Stats()
+}
+
+func (d *runcDriver) MemoryPressure(id string, level libcontainer.PressureLevel) (<-chan struct{}, error) {
+	container, err := d.container(id)
+	if err != nil {
+		return nil, err
+	}
+	return container.NotifyMemoryPressure(level)
+}
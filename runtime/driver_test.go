@@ -0,0 +1,75 @@
+package runtime
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestNewDriverDefaultsToRunc(t *testing.T) {
+	if _, ok := NewDriver("", nil).(*runcDriver); !ok {
+		t.Fatal("expected containers without a runtime to be run by runc")
+	}
+	if _, ok := NewDriver("/usr/local/bin/runc", nil).(*runcDriver); !ok {
+		t.Fatal("expected a path to runc to be run by runc")
+	}
+	if _, ok := NewDriver("/usr/bin/runsc", nil).(*ociDriver); !ok {
+		t.Fatal("expected a selected runtime to be run by the oci driver")
+	}
+}
+
+func TestOCIDriverReadsRuntimeState(t *testing.T) {
+	dir, err := ioutil.TempDir("", "containerd-driver")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	// the runtime reports this process as the container's init process
+	binary := filepath.Join(dir, "runtime")
+	script := fmt.Sprintf("#!/bin/sh\necho \"$@\" >> %s\necho '{\"pid\": %d, \"status\": \"paused\"}'\n", filepath.Join(dir, "args"), os.Getpid())
+	if err := ioutil.WriteFile(binary, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	d := NewDriver(binary, []string{"--debug"})
+	paused, err := d.Paused("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !paused {
+		t.Fatal("expected the container to be paused")
+	}
+	args, err := ioutil.ReadFile(filepath.Join(dir, "args"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(args) != "--debug state test\n" {
+		t.Fatalf("expected the runtime args before the state command but received %q", args)
+	}
+	if _, err := d.CgroupPaths("test"); err != nil {
+		t.Fatal(err)
+	}
+	// the cgroups are found from the state that was read for Paused
+	if args, err = ioutil.ReadFile(filepath.Join(dir, "args")); err != nil {
+		t.Fatal(err)
+	}
+	if string(args) != "--debug state test\n" {
+		t.Fatalf("expected the runtime state to be cached but the runtime was run with %q", args)
+	}
+}
+
+func TestOCIDriverCommands(t *testing.T) {
+	d := NewDriver("/usr/bin/runsc", nil)
+	cmd := d.Exec("test", ExecOpts{Process: "process.json", Console: "/dev/pts/1", PidFile: "pid"})
+	expected := []string{"/usr/bin/runsc", "exec", "--process", "process.json", "--console", "/dev/pts/1", "-d", "--pid-file", "pid", "test"}
+	if !reflect.DeepEqual(cmd.Args, expected) {
+		t.Fatalf("expected %v but received %v", expected, cmd.Args)
+	}
+	cmd = d.Create("test", CreateOpts{PidFile: "pid", Checkpoint: &Checkpoint{Name: "cpt", Path: "/checkpoints", Tcp: true}})
+	expected = []string{"/usr/bin/runsc", "restore", "--image-path", "/checkpoints/cpt", "--tcp-established", "-d", "--pid-file", "pid", "test"}
+	if !reflect.DeepEqual(cmd.Args, expected) {
+		t.Fatalf("expected %v but received %v", expected, cmd.Args)
+	}
+}
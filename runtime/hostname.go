@@ -0,0 +1,112 @@
+package runtime
+
+import (
+	"fmt"
+	"os"
+	goruntime "runtime"
+	"strings"
+	"syscall"
+
+	"github.com/opencontainers/runc/libcontainer/system"
+)
+
+// maxHostnameLength is HOST_NAME_MAX on linux
+const maxHostnameLength = 64
+
+// ValidateHostname returns ErrInvalidHostname if name is not a valid RFC 1123
+// hostname
+func ValidateHostname(name string) error {
+	if name == "" || len(name) > maxHostnameLength {
+		return ErrInvalidHostname
+	}
+	for _, label := range strings.Split(name, ".") {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return ErrInvalidHostname
+		}
+		for _, r := range label {
+			switch {
+			case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-':
+			default:
+				return ErrInvalidHostname
+			}
+		}
+	}
+	return nil
+}
+
+func (c *container) Hostname() (string, error) {
+	var uts syscall.Utsname
+	if err := c.inUTSNamespace(func() error {
+		return syscall.Uname(&uts)
+	}); err != nil {
+		return "", err
+	}
+	name := make([]byte, 0, len(uts.Nodename))
+	for _, b := range uts.Nodename {
+		if b == 0 {
+			break
+		}
+		name = append(name, byte(b))
+	}
+	return string(name), nil
+}
+
+func (c *container) SetHostname(name string) error {
+	if err := ValidateHostname(name); err != nil {
+		return err
+	}
+	return c.inUTSNamespace(func() error {
+		return syscall.Sethostname([]byte(name))
+	})
+}
+
+// inUTSNamespace runs fn on a thread that has joined the UTS namespace of the
+// container's init process.  The thread is never returned to the daemon's
+// namespace, it is locked to a goroutine that exits so that the thread exits
+// with it.
+func (c *container) inUTSNamespace(fn func() error) error {
+	p, ok := c.initProcess()
+	if !ok {
+		return ErrContainerNotStarted
+	}
+	if _, err := p.ExitStatus(); err == nil {
+		return ErrContainerExited
+	}
+	path := fmt.Sprintf("/proc/%d/ns/uts", p.SystemPid())
+	shared, err := sameNamespace(path, "/proc/self/ns/uts")
+	if err != nil {
+		return err
+	}
+	if shared {
+		return ErrHostUTSNamespace
+	}
+	target, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer target.Close()
+	errCh := make(chan error, 1)
+	go func() {
+		goruntime.LockOSThread()
+		if err := system.Setns(target.Fd(), syscall.CLONE_NEWUTS); err != nil {
+			errCh <- err
+			return
+		}
+		errCh <- fn()
+	}()
+	return <-errCh
+}
+
+// sameNamespace returns true if the namespace files a and b refer to the same
+// namespace
+func sameNamespace(a, b string) (bool, error) {
+	ai, err := os.Stat(a)
+	if err != nil {
+		return false, err
+	}
+	bi, err := os.Stat(b)
+	if err != nil {
+		return false, err
+	}
+	return os.SameFile(ai, bi), nil
+}
@@ -0,0 +1,55 @@
+package runtime
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/opencontainers/specs"
+)
+
+const (
+	// InitBundleDir is the directory in the container's state directory holding the
+	// bundle that starts the init process under the init wrapper
+	InitBundleDir = "init-bundle"
+	// InitWrapperPath is where the init wrapper is mounted in the container
+	InitWrapperPath = "/dev/init"
+)
+
+// wrapInit returns a copy of the spec that runs its process under the init wrapper.
+// The wrapper is bind mounted into the container and runs the original process
+// after "--" the same as tini.
+func (c *container) wrapInit(spec *specs.LinuxSpec) *specs.LinuxSpec {
+	wrapped := *spec
+	wrapped.Process.Args = append([]string{InitWrapperPath, "--"}, spec.Process.Args...)
+	// the generated bundle is not next to the rootfs so its path must be absolute
+	if !filepath.IsAbs(wrapped.Root.Path) {
+		wrapped.Root.Path = filepath.Join(c.bundle, wrapped.Root.Path)
+	}
+	// the wrapper is mounted last so that it is not hidden by the mount of /dev
+	wrapped.Mounts = append(append([]specs.Mount{}, spec.Mounts...), specs.Mount{
+		Destination: InitWrapperPath,
+		Type:        "bind",
+		Source:      c.initWrapper,
+		Options:     []string{"bind", "ro"},
+	})
+	return &wrapped
+}
+
+// writeInitBundle writes the bundle for the spec wrapped by the init wrapper to the
+// container's state directory and returns its path
+func (c *container) writeInitBundle(spec *specs.LinuxSpec) (string, error) {
+	dir := filepath.Join(c.root, c.id, InitBundleDir)
+	if err := os.Mkdir(dir, 0755); err != nil && !os.IsExist(err) {
+		return "", err
+	}
+	f, err := os.Create(filepath.Join(dir, "config.json"))
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	if err := json.NewEncoder(f).Encode(spec); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
@@ -0,0 +1,19 @@
+package runtime
+
+import "github.com/opencontainers/runc/libcontainer"
+
+// MemoryPressureLevels are the memory pressure levels of a memory cgroup that
+// can be watched with MemoryPressure
+var MemoryPressureLevels = map[string]libcontainer.PressureLevel{
+	"low":      libcontainer.LowPressure,
+	"medium":   libcontainer.MediumPressure,
+	"critical": libcontainer.CriticalPressure,
+}
+
+func (c *container) MemoryPressure(level string) (<-chan struct{}, error) {
+	l, ok := MemoryPressureLevels[level]
+	if !ok {
+		return nil, ErrInvalidPressureLevel
+	}
+	return c.driver.MemoryPressure(c.id, l)
+}
@@ -22,6 +22,8 @@ type Process interface {
 	// it is a user provided id for the process similar to the container id
 	ID() string
 	CloseStdin() error
+	// Resize sets the width and height of the process's terminal, it returns
+	// ErrTerminalsNotSupported if the process does not have a terminal
 	Resize(int, int) error
 	// ExitFD returns the fd the provides an event when the process exits
 	ExitFD() int
@@ -38,6 +40,13 @@ type Process interface {
 	Stdio() Stdio
 	// SystemPid is the pid on the system
 	SystemPid() int
+	// Invocation returns the runtime command that was executed to start the process
+	Invocation() (*Invocation, error)
+	// MarkExitDelivered records that the exit of the process has been delivered so
+	// that it is not delivered again after a restart
+	MarkExitDelivered() error
+	// ExitDelivered returns true if the exit of the process has been delivered
+	ExitDelivered() bool
 }
 
 type processConfig struct {
@@ -48,7 +57,7 @@ type processConfig struct {
 	c           *container
 	stdio       Stdio
 	exec        bool
-	checkpoint  string
+	checkpoint  *Checkpoint
 }
 
 func newProcess(config *processConfig) (*process, error) {
@@ -68,16 +77,22 @@ func newProcess(config *processConfig) (*process, error) {
 		return nil, err
 	}
 	defer f.Close()
-	if err := json.NewEncoder(f).Encode(ProcessState{
-		Process:    config.processSpec,
-		Exec:       config.exec,
-		Checkpoint: config.checkpoint,
-		RootUID:    uid,
-		RootGID:    gid,
-		Stdin:      config.stdio.Stdin,
-		Stdout:     config.stdio.Stdout,
-		Stderr:     config.stdio.Stderr,
-	}); err != nil {
+	state := ProcessState{
+		Process:     config.processSpec,
+		Exec:        config.exec,
+		RootUID:     uid,
+		RootGID:     gid,
+		Stdin:       config.stdio.Stdin,
+		Stdout:      config.stdio.Stdout,
+		Stderr:      config.stdio.Stderr,
+		RuntimeArgs: config.c.runtimeArgs,
+		Runtime:     config.c.runtime,
+	}
+	if config.checkpoint != nil {
+		state.Checkpoint = config.checkpoint.Name
+		state.CheckpointPath = config.checkpoint.Path
+	}
+	if err := json.NewEncoder(f).Encode(state); err != nil {
 		return nil, err
 	}
 	exit, err := getExitPipe(filepath.Join(config.root, ExitFile))
@@ -172,6 +187,9 @@ func (p *process) CloseStdin() error {
 }
 
 func (p *process) Resize(w, h int) error {
+	if !p.spec.Terminal {
+		return ErrTerminalsNotSupported
+	}
 	_, err := fmt.Fprintf(p.controlPipe, "%d %d %d\n", 1, w, h)
 	return err
 }
@@ -190,6 +208,19 @@ func (p *process) ExitStatus() (int, error) {
 	return strconv.Atoi(string(data))
 }
 
+func (p *process) MarkExitDelivered() error {
+	f, err := os.Create(filepath.Join(p.root, ExitDeliveredFile))
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+func (p *process) ExitDelivered() bool {
+	_, err := os.Stat(filepath.Join(p.root, ExitDeliveredFile))
+	return err == nil
+}
+
 // Signal sends the provided signal to the process
 func (p *process) Signal(s os.Signal) error {
 	return syscall.Kill(p.pid, s.(syscall.Signal))
@@ -203,11 +234,32 @@ func (p *process) Stdio() Stdio {
 	return p.stdio
 }
 
+func (p *process) Invocation() (*Invocation, error) {
+	f, err := os.Open(filepath.Join(p.root, InvocationFile))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var i Invocation
+	if err := json.NewDecoder(f).Decode(&i); err != nil {
+		return nil, err
+	}
+	return &i, nil
+}
+
 // Close closes any open files and/or resouces on the process
 func (p *process) Close() error {
 	return p.exitPipe.Close()
 }
 
+// closePipes closes the pipes of a process that failed to start
+func (p *process) closePipes() {
+	p.exitPipe.Close()
+	if p.controlPipe != nil {
+		p.controlPipe.Close()
+	}
+}
+
 func (p *process) getPid() (int, error) {
 	for i := 0; i < 20; i++ {
 		data, err := ioutil.ReadFile(filepath.Join(p.root, "pid"))
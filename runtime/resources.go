@@ -0,0 +1,164 @@
+package runtime
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/opencontainers/runc/libcontainer/cgroups"
+)
+
+// Resources are the cgroup limits of a container that can be updated while it is
+// running.  Zero values leave the limit unchanged and -1 removes a limit.
+type Resources struct {
+	// CpuShares is the relative cpu weight of the container
+	CpuShares int64
+	// CpuQuota is the cpu time in microseconds the container can use in each
+	// CpuPeriod
+	CpuQuota  int64
+	CpuPeriod int64
+	// MemoryLimit is the maximum memory usage of the container in bytes
+	MemoryLimit int64
+	// PidsLimit is the maximum number of tasks in the container
+	PidsLimit int64
+}
+
+// Validate returns ErrInvalidResources if the kernel would reject a limit
+func (r Resources) Validate() error {
+	switch {
+	case r.CpuShares < 0 || r.CpuShares == 1:
+		return ErrInvalidResources
+	case r.CpuQuota < -1 || (r.CpuQuota > 0 && r.CpuQuota < 1000):
+		return ErrInvalidResources
+	case r.CpuPeriod < 0 || (r.CpuPeriod > 0 && (r.CpuPeriod < 1000 || r.CpuPeriod > 1000000)):
+		return ErrInvalidResources
+	case r.MemoryLimit < -1 || r.PidsLimit < -1:
+		return ErrInvalidResources
+	}
+	return nil
+}
+
+// unlimitedMemory is the smallest value that the kernel reports for a memory cgroup
+// without a limit, the exact value depends on the page size
+const unlimitedMemory = 1 << 62
+
+// Resources returns the limits of the container's cgroups with -1 for a limit that
+// is not set.  The limits of cgroups that are not available are 0.
+func (c *container) Resources() (Resources, error) {
+	paths, err := c.driver.CgroupPaths(c.id)
+	if err != nil {
+		return Resources{}, err
+	}
+	read := func(subsystem, file string) int64 {
+		path, ok := paths[subsystem]
+		if !ok {
+			return 0
+		}
+		data, err := ioutil.ReadFile(filepath.Join(path, file))
+		if err != nil {
+			return 0
+		}
+		value := strings.TrimSpace(string(data))
+		if value == "max" {
+			return -1
+		}
+		v, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return 0
+		}
+		return v
+	}
+	r := Resources{
+		CpuShares:   read("cpu", "cpu.shares"),
+		CpuQuota:    read("cpu", "cpu.cfs_quota_us"),
+		CpuPeriod:   read("cpu", "cpu.cfs_period_us"),
+		MemoryLimit: read("memory", "memory.limit_in_bytes"),
+		PidsLimit:   read("pids", "pids.max"),
+	}
+	if r.MemoryLimit >= unlimitedMemory {
+		r.MemoryLimit = -1
+	}
+	return r, nil
+}
+
+// UpdateResources checks that every changed limit can be applied before any limit
+// is written so that a rejected update leaves the container's limits unchanged
+func (c *container) UpdateResources(r Resources) error {
+	if err := r.Validate(); err != nil {
+		return err
+	}
+	paths, err := c.driver.CgroupPaths(c.id)
+	if err != nil {
+		return err
+	}
+	cgroup := func(name string) (string, error) {
+		path, ok := paths[name]
+		if !ok || !cgroups.PathExists(path) {
+			return "", ErrCgroupNotSupported
+		}
+		return path, nil
+	}
+	var writes []cgroupWrite
+	if r.CpuShares != 0 || r.CpuQuota != 0 || r.CpuPeriod != 0 {
+		path, err := cgroup("cpu")
+		if err != nil {
+			return err
+		}
+		// the period is written first so that the quota is checked against it
+		for _, v := range []struct {
+			file  string
+			value int64
+		}{
+			{"cpu.cfs_period_us", r.CpuPeriod},
+			{"cpu.cfs_quota_us", r.CpuQuota},
+			{"cpu.shares", r.CpuShares},
+		} {
+			if v.value != 0 {
+				writes = append(writes, cgroupWrite{path, v.file, strconv.FormatInt(v.value, 10)})
+			}
+		}
+	}
+	if r.MemoryLimit != 0 {
+		path, err := cgroup("memory")
+		if err != nil {
+			return err
+		}
+		if r.MemoryLimit > 0 {
+			data, err := ioutil.ReadFile(filepath.Join(path, "memory.usage_in_bytes"))
+			if err != nil {
+				return err
+			}
+			usage, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+			if err != nil {
+				return err
+			}
+			if usage > r.MemoryLimit {
+				return ErrMemoryBelowUsage
+			}
+		}
+		writes = append(writes, cgroupWrite{path, "memory.limit_in_bytes", strconv.FormatInt(r.MemoryLimit, 10)})
+	}
+	if r.PidsLimit != 0 {
+		path, err := c.pidsCgroup()
+		if err != nil {
+			return err
+		}
+		max := "max"
+		if r.PidsLimit > 0 {
+			max = strconv.FormatInt(r.PidsLimit, 10)
+		}
+		writes = append(writes, cgroupWrite{path, "pids.max", max})
+	}
+	for _, w := range writes {
+		if err := ioutil.WriteFile(filepath.Join(w.path, w.file), []byte(w.value), 0); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cgroupWrite is a value to write to a file of a cgroup
+type cgroupWrite struct {
+	path, file, value string
+}
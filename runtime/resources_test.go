@@ -0,0 +1,55 @@
+package runtime
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// cgroupDriver reports directories as the cgroups of the container
+type cgroupDriver struct {
+	RuntimeDriver
+	paths map[string]string
+}
+
+func (d *cgroupDriver) CgroupPaths(id string) (map[string]string, error) {
+	return d.paths, nil
+}
+
+func TestUpdateResourcesValidatesBeforeWriting(t *testing.T) {
+	dir, err := ioutil.TempDir("", "containerd-resources")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	paths := make(map[string]string)
+	for _, subsystem := range []string{"cpu", "memory"} {
+		paths[subsystem] = filepath.Join(dir, subsystem)
+		if err := os.Mkdir(paths[subsystem], 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for file, value := range map[string]string{
+		"cpu/cpu.shares":               "1024",
+		"memory/memory.usage_in_bytes": "4096",
+	} {
+		if err := ioutil.WriteFile(filepath.Join(dir, file), []byte(value), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c := &container{id: "test", driver: &cgroupDriver{paths: paths}}
+	if err := c.UpdateResources(Resources{CpuShares: 512, MemoryLimit: 1024}); err != ErrMemoryBelowUsage {
+		t.Fatalf("expected ErrMemoryBelowUsage but received %v", err)
+	}
+	if err := c.UpdateResources(Resources{CpuShares: 512, PidsLimit: 10}); err != ErrPidsNotSupported {
+		t.Fatalf("expected ErrPidsNotSupported but received %v", err)
+	}
+	data, err := ioutil.ReadFile(filepath.Join(paths["cpu"], "cpu.shares"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "1024" {
+		t.Fatalf("expected the cpu shares to be unchanged but received %s", data)
+	}
+}
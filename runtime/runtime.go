@@ -4,6 +4,7 @@ import (
 	"errors"
 	"time"
 
+	"github.com/opencontainers/runc/libcontainer"
 	"github.com/opencontainers/specs"
 )
 
@@ -16,6 +17,17 @@ var (
 	ErrTerminalsNotSupported = errors.New("containerd: terminals are not supported for runtime")
 	ErrProcessNotExited      = errors.New("containerd: process has not exited")
 	ErrProcessExited         = errors.New("containerd: process has exited")
+	ErrPidsNotSupported      = errors.New("containerd: pids cgroup is not available for container")
+	ErrFreezerNotSupported   = errors.New("containerd: freezer cgroup is not available for container")
+	ErrTimeout               = errors.New("containerd: runtime operation timed out")
+	ErrCanceled              = errors.New("containerd: runtime operation canceled")
+	ErrContainerNotStarted   = errors.New("containerd: container has not been started")
+	ErrInvalidHostname       = errors.New("containerd: invalid hostname")
+	ErrHostUTSNamespace      = errors.New("containerd: container shares the daemon's UTS namespace")
+	ErrInvalidResources      = errors.New("containerd: invalid resource limits")
+	ErrMemoryBelowUsage      = errors.New("containerd: memory limit is below the current usage")
+	ErrCgroupNotSupported    = errors.New("containerd: cgroup is not available for container")
+	ErrInvalidPressureLevel  = errors.New("containerd: invalid memory pressure level")
 
 	errNotImplemented = errors.New("containerd: not implemented")
 )
@@ -26,42 +38,101 @@ const (
 	StateFile      = "state.json"
 	ControlFile    = "control"
 	InitProcessID  = "init"
+	// InvocationFile records how the shim invoked the runtime for a process
+	InvocationFile = "invocation.json"
+	// ExitDeliveredFile marks a process whose exit event has been delivered
+	ExitDeliveredFile = "exitDelivered"
+	// ShimLogFile holds the output of the shim of an exec process
+	ShimLogFile = "shim.log"
 )
 
 type State string
 
 const (
+	Created = State("created")
 	Paused  = State("paused")
 	Running = State("running")
+	Stopped = State("stopped")
 )
 
 type state struct {
-	Bundle string   `json:"bundle"`
-	Labels []string `json:"labels"`
-	Stdin  string   `json:"stdin"`
-	Stdout string   `json:"stdout"`
-	Stderr string   `json:"stderr"`
+	Bundle      string   `json:"bundle"`
+	Labels      []string `json:"labels"`
+	Stdin       string   `json:"stdin"`
+	Stdout      string   `json:"stdout"`
+	Stderr      string   `json:"stderr"`
+	RuntimeArgs []string `json:"runtimeArgs,omitempty"`
+	InitWrapper string   `json:"initWrapper,omitempty"`
+	Runtime     string   `json:"runtime,omitempty"`
 }
 
 type ProcessState struct {
 	specs.Process
 	Exec       bool   `json:"exec"`
 	Checkpoint string `json:"checkpoint"`
-	RootUID    int    `json:"rootUID"`
-	RootGID    int    `json:"rootGID"`
-	Stdin      string `json:"containerdStdin"`
-	Stdout     string `json:"containerdStdout"`
-	Stderr     string `json:"containerdStderr"`
+	// CheckpointPath is the directory that the restored checkpoint is stored in,
+	// the checkpoints directory of the bundle when empty
+	CheckpointPath string `json:"checkpointPath,omitempty"`
+	RootUID        int    `json:"rootUID"`
+	RootGID        int    `json:"rootGID"`
+	Stdin          string `json:"containerdStdin"`
+	Stdout         string `json:"containerdStdout"`
+	Stderr         string `json:"containerdStderr"`
+	// RuntimeArgs are passed to the runtime before its command
+	RuntimeArgs []string `json:"runtimeArgs,omitempty"`
+	// Runtime is the OCI runtime binary that runs the process
+	Runtime string `json:"runtime,omitempty"`
+}
+
+// DefaultRuntime is the OCI runtime binary for containers that do not select one
+const DefaultRuntime = "runc"
+
+// The phases of creating a checkpoint
+const (
+	// CheckpointPhasePrepare writes the checkpoint's config
+	CheckpointPhasePrepare = "prepare"
+	// CheckpointPhaseDump runs the runtime to dump the container
+	CheckpointPhaseDump = "dump"
+	// CheckpointPhaseCommit moves the complete checkpoint into place
+	CheckpointPhaseCommit = "commit"
+)
+
+// CheckpointOpts control the creation of a checkpoint
+type CheckpointOpts struct {
+	// Progress is called with each phase of the checkpoint as the phase starts
+	Progress func(phase string)
+	// Cancel kills the runtime if it is closed before the dump is complete, the
+	// container is thawed and ErrCanceled is returned
+	Cancel <-chan struct{}
+}
+
+func (o CheckpointOpts) progress(phase string) {
+	if o.Progress != nil {
+		o.Progress(phase)
+	}
+}
+
+// Invocation is a command as it was executed
+type Invocation struct {
+	Path string   `json:"path"`
+	Args []string `json:"args"`
+	// Env holds the variables set in addition to the environment inherited by the command
+	Env []string `json:"env,omitempty"`
+	Dir string   `json:"dir"`
 }
 
 type Stat struct {
 	// Timestamp is the time that the statistics where collected
 	Timestamp time.Time
-	// Data is the raw stats
-	// TODO: it is currently an interface because we don't know what type of exec drivers
-	// we will have or what the structure should look like at the moment os the containers
-	// can return what they want and we could marshal to json or whatever.
+	// Data is a *CgroupStats, it is an interface for compatibility and is best
+	// accessed with Cgroup
 	Data interface{}
+	// Raw holds the stats as collected from libcontainer, it is not marshaled as
+	// its format changes with libcontainer
+	Raw *libcontainer.Stats `json:"-"`
+	// Available reports for each cgroup controller if its stats were collected,
+	// stats are still returned when some controllers are missing
+	Available map[string]bool
 }
 
 type Checkpoint struct {
@@ -77,4 +148,8 @@ type Checkpoint struct {
 	Shell bool `json:"shell"`
 	// Exit exits the container after the checkpoint is finished
 	Exit bool `json:"exit"`
+	// Path is the directory that the checkpoint is stored in, such as a shared
+	// mount to restore the checkpoint on another host.  The checkpoints directory
+	// of the container's bundle is used when it is empty.
+	Path string `json:"path,omitempty"`
 }
@@ -0,0 +1,139 @@
+package runtime
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/opencontainers/runc/libcontainer"
+)
+
+// CgroupStats are the stats of a container, they are the Data of a Stat
+type CgroupStats struct {
+	Cpu     CpuStats         `json:"cpu"`
+	Memory  MemoryStats      `json:"memory"`
+	Pids    PidsStats        `json:"pids"`
+	Network []InterfaceStats `json:"network,omitempty"`
+}
+
+// CpuStats is the cpu time used by the container in nanoseconds
+type CpuStats struct {
+	Total  uint64 `json:"total"`
+	User   uint64 `json:"user"`
+	System uint64 `json:"system"`
+}
+
+// MemoryStats is the memory used by the container in bytes
+type MemoryStats struct {
+	Usage uint64 `json:"usage"`
+	Limit uint64 `json:"limit"`
+	Cache uint64 `json:"cache"`
+	Rss   uint64 `json:"rss"`
+}
+
+type PidsStats struct {
+	Current uint64 `json:"current"`
+}
+
+// InterfaceStats are the stats of one of the network interfaces in the container's
+// network namespace
+type InterfaceStats struct {
+	Name      string `json:"name"`
+	RxBytes   uint64 `json:"rxBytes"`
+	RxPackets uint64 `json:"rxPackets"`
+	RxErrors  uint64 `json:"rxErrors"`
+	RxDropped uint64 `json:"rxDropped"`
+	TxBytes   uint64 `json:"txBytes"`
+	TxPackets uint64 `json:"txPackets"`
+	TxErrors  uint64 `json:"txErrors"`
+	TxDropped uint64 `json:"txDropped"`
+}
+
+// Cgroup returns the stats as CgroupStats, it returns false if the stats are
+// of another type
+func (s *Stat) Cgroup() (*CgroupStats, bool) {
+	st, ok := s.Data.(*CgroupStats)
+	return st, ok
+}
+
+func newCgroupStats(lst *libcontainer.Stats) *CgroupStats {
+	st := &CgroupStats{}
+	if cg := lst.CgroupStats; cg != nil {
+		st.Cpu = CpuStats{
+			Total:  cg.CpuStats.CpuUsage.TotalUsage,
+			User:   cg.CpuStats.CpuUsage.UsageInUsermode,
+			System: cg.CpuStats.CpuUsage.UsageInKernelmode,
+		}
+		st.Memory = MemoryStats{
+			Usage: cg.MemoryStats.Usage.Usage,
+			Limit: cg.MemoryStats.Usage.Limit,
+			Cache: cg.MemoryStats.Cache,
+			Rss:   cg.MemoryStats.Stats["rss"],
+		}
+		st.Pids.Current = cg.PidsStats.Current
+	}
+	for _, i := range lst.Interfaces {
+		st.Network = append(st.Network, InterfaceStats{
+			Name:      i.Name,
+			RxBytes:   i.RxBytes,
+			RxPackets: i.RxPackets,
+			RxErrors:  i.RxErrors,
+			RxDropped: i.RxDropped,
+			TxBytes:   i.TxBytes,
+			TxPackets: i.TxPackets,
+			TxErrors:  i.TxErrors,
+			TxDropped: i.TxDropped,
+		})
+	}
+	return st
+}
+
+// readNetDev returns the stats of the interfaces in the network namespace of the
+// process pid from /proc/<pid>/net/dev
+func readNetDev(pid int) ([]InterfaceStats, error) {
+	f, err := os.Open(fmt.Sprintf("/proc/%d/net/dev", pid))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var (
+		out []InterfaceStats
+		s   = bufio.NewScanner(f)
+	)
+	for i := 0; s.Scan(); i++ {
+		// the first two lines are headers
+		if i < 2 {
+			continue
+		}
+		parts := strings.SplitN(s.Text(), ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("containerd: invalid net/dev line %q", s.Text())
+		}
+		fields := strings.Fields(parts[1])
+		if len(fields) < 12 {
+			return nil, fmt.Errorf("containerd: invalid net/dev line %q", s.Text())
+		}
+		var values [12]uint64
+		for j := range values {
+			if values[j], err = strconv.ParseUint(fields[j], 10, 64); err != nil {
+				return nil, err
+			}
+		}
+		// receive columns are bytes, packets, errs, drop, fifo, frame, compressed,
+		// multicast followed by the transmit columns
+		out = append(out, InterfaceStats{
+			Name:      strings.TrimSpace(parts[0]),
+			RxBytes:   values[0],
+			RxPackets: values[1],
+			RxErrors:  values[2],
+			RxDropped: values[3],
+			TxBytes:   values[8],
+			TxPackets: values[9],
+			TxErrors:  values[10],
+			TxDropped: values[11],
+		})
+	}
+	return out, s.Err()
+}
@@ -1,37 +1,120 @@
 package supervisor
 
 import (
+	"strconv"
 	"time"
 
 	"github.com/docker/containerd/runtime"
 )
 
+// WithExecLimit limits the number of exec processes that are started at the same
+// time across all containers, each exec forks the runtime.  When queue is set
+// execs over the limit wait for a running exec to finish starting, otherwise they
+// fail with ErrTooManyExecs.  Without a limit execs are started one at a time in
+// the event loop.
+func WithExecLimit(max int, queue bool) Option {
+	return func(s *Supervisor) {
+		if max > 0 {
+			s.execSlots = make(chan struct{}, max)
+			s.execQueue = queue
+		}
+	}
+}
+
 type AddProcessTask struct {
 	s *Supervisor
 }
 
-// TODO: add this to worker for concurrent starts???  maybe not because of races where the container
-// could be stopped and removed...
+// Handle starts the process and returns it in e.StartResponse once the runtime has
+// started it, or the runtime's error if it failed to start.  With an exec limit
+// the process is started off the event loop, the container is not deleted until
+// the exec is done.
 func (h *AddProcessTask) Handle(e *Task) error {
 	start := time.Now()
 	ci, ok := h.s.containers[e.ID]
 	if !ok {
 		return ErrContainerNotFound
 	}
-	process, err := ci.container.Exec(e.Pid, *e.ProcessSpec, runtime.NewStdio(e.Stdin, e.Stdout, e.Stderr))
+	if h.s.execSlots == nil {
+		return h.s.addProcess(ci, e, start)
+	}
+	if !h.s.execQueue {
+		select {
+		case h.s.execSlots <- struct{}{}:
+		default:
+			return ErrTooManyExecs
+		}
+	}
+	go func() {
+		if h.s.execQueue {
+			h.s.execSlots <- struct{}{}
+		}
+		err := h.s.addProcess(ci, e, start)
+		<-h.s.execSlots
+		e.Err <- err
+	}()
+	return errDeferedResponse
+}
+
+// addProcess starts the process and sends the result in e.StartResponse, with the
+// error if the process could not be started
+func (s *Supervisor) addProcess(ci *containerInfo, e *Task, start time.Time) (err error) {
+	defer func() {
+		if err != nil {
+			e.StartResponse <- StartResponse{Err: err}
+		}
+	}()
+	ci.execLock.RLock()
+	defer ci.execLock.RUnlock()
+	if ci.deleted {
+		return ErrContainerNotFound
+	}
+	stdio := runtime.NewStdio(e.Stdin, e.Stdout, e.Stderr)
+	var capture *outputCapture
+	if e.CaptureOutput {
+		c, err := newOutputCapture(s.captureLimit)
+		if err != nil {
+			return err
+		}
+		capture = c
+		stdio = runtime.NewStdio(e.Stdin, c.stdoutPath(), c.stderrPath())
+	}
+	process, err := ci.container.Exec(e.Pid, *e.ProcessSpec, stdio)
 	if err != nil {
+		if capture != nil {
+			capture.close()
+		}
 		return err
 	}
-	if err := h.s.monitorProcess(process); err != nil {
+	if capture != nil {
+		capture.started()
+		s.capturesLock.Lock()
+		s.captures[process] = capture
+		s.capturesLock.Unlock()
+	}
+	if err := s.monitorProcess(process); err != nil {
+		if capture != nil {
+			s.capturesLock.Lock()
+			delete(s.captures, process)
+			s.capturesLock.Unlock()
+			capture.close()
+		}
 		return err
 	}
 	ExecProcessTimer.UpdateSince(start)
-	e.StartResponse <- StartResponse{}
-	h.s.notifySubscribers(Event{
+	s.updateSnapshot(ci.container, "")
+	e.StartResponse <- StartResponse{
+		Container: ci.container,
+		Process:   process,
+	}
+	s.notifySubscribers(withCorrelationID(Event{
 		Timestamp: time.Now(),
 		Type:      "start-process",
-		Pid:       e.Pid,
-		ID:        e.ID,
-	})
+		Metadata: map[string]string{
+			systemPidKey: strconv.Itoa(process.SystemPid()),
+		},
+		Pid: e.Pid,
+		ID:  e.ID,
+	}, e.CorrelationID))
 	return nil
 }
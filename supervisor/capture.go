@@ -0,0 +1,162 @@
+package supervisor
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+)
+
+const (
+	defaultCaptureLimit = 64 * 1024
+	// captureWaitTimeout is how long to wait for the captured output after an exec
+	// process exits as children of the process can keep its stdio open
+	captureWaitTimeout = time.Second
+)
+
+// WithExecCaptureLimit sets the maximum number of bytes kept from each of stdout
+// and stderr of an exec process that captures its output, a limit below 0 keeps
+// nothing
+func WithExecCaptureLimit(n int) Option {
+	return func(s *Supervisor) {
+		if n < 0 {
+			n = 0
+		}
+		s.captureLimit = n
+	}
+}
+
+// outputCapture collects the stdout and stderr of an exec process from fifos
+type outputCapture struct {
+	dir     string
+	stdout  *limitedBuffer
+	stderr  *limitedBuffer
+	readers []*os.File
+	writers []*os.File
+	wg      sync.WaitGroup
+}
+
+func newOutputCapture(limit int) (*outputCapture, error) {
+	if limit < 0 {
+		limit = 0
+	}
+	dir, err := ioutil.TempDir("", "containerd-exec")
+	if err != nil {
+		return nil, err
+	}
+	c := &outputCapture{
+		dir:    dir,
+		stdout: &limitedBuffer{limit: limit},
+		stderr: &limitedBuffer{limit: limit},
+	}
+	for _, b := range []*limitedBuffer{c.stdout, c.stderr} {
+		if err := c.read(b); err != nil {
+			c.close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// read creates a fifo and copies everything written to it into b
+func (c *outputCapture) read(b *limitedBuffer) error {
+	path := filepath.Join(c.dir, []string{"stdout", "stderr"}[len(c.readers)])
+	if err := syscall.Mkfifo(path, 0600); err != nil {
+		return err
+	}
+	r, err := os.OpenFile(path, syscall.O_RDONLY|syscall.O_NONBLOCK, 0)
+	if err != nil {
+		return err
+	}
+	c.readers = append(c.readers, r)
+	// hold a writer open so that the reader does not see EOF before the shim
+	// opens the fifo, it is closed once the process is started
+	w, err := os.OpenFile(path, syscall.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	c.writers = append(c.writers, w)
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		io.Copy(b, r)
+	}()
+	return nil
+}
+
+func (c *outputCapture) stdoutPath() string {
+	return filepath.Join(c.dir, "stdout")
+}
+
+func (c *outputCapture) stderrPath() string {
+	return filepath.Join(c.dir, "stderr")
+}
+
+// started closes the capture's writers once the process holds the fifos open
+func (c *outputCapture) started() {
+	for _, w := range c.writers {
+		w.Close()
+	}
+	c.writers = nil
+}
+
+// wait waits for the process's output to be read up to timeout
+func (c *outputCapture) wait(timeout time.Duration) {
+	done := make(chan struct{})
+	go func() {
+		c.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		logrus.WithField("dir", c.dir).Warn("containerd: exec output still open after exit")
+	}
+}
+
+func (c *outputCapture) close() {
+	c.started()
+	for _, r := range c.readers {
+		r.Close()
+	}
+	if err := os.RemoveAll(c.dir); err != nil {
+		logrus.WithField("error", err).Error("containerd: remove exec output fifos")
+	}
+}
+
+// limitedBuffer keeps the first limit bytes written to it and discards the rest
+type limitedBuffer struct {
+	mu        sync.Mutex
+	limit     int
+	data      []byte
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	n := len(p)
+	if free := b.limit - len(b.data); n > free {
+		p = p[:free]
+		b.truncated = true
+	}
+	b.data = append(b.data, p...)
+	return n, nil
+}
+
+func (b *limitedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return string(b.data)
+}
+
+func (b *limitedBuffer) Truncated() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.truncated
+}
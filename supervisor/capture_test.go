@@ -0,0 +1,42 @@
+package supervisor
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestNegativeCaptureLimit(t *testing.T) {
+	dir, err := ioutil.TempDir("", "containerd-capture")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	s, err := New(dir, false, WithExecCaptureLimit(-1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Start()
+	defer s.Close()
+	if s.captureLimit != 0 {
+		t.Fatalf("expected a capture limit of 0 but received %d", s.captureLimit)
+	}
+	c, err := newOutputCapture(-1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.close()
+	n, err := c.stdout.Write([]byte("output"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != len("output") {
+		t.Fatalf("expected the write to be consumed but received %d", n)
+	}
+	if out := c.stdout.String(); out != "" {
+		t.Fatalf("expected no output to be kept but received %q", out)
+	}
+	if !c.stdout.Truncated() {
+		t.Fatal("expected the output to be truncated")
+	}
+}
@@ -0,0 +1,287 @@
+package supervisor
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/containerd/runtime"
+	"github.com/docker/docker/pkg/mount"
+	"github.com/opencontainers/runc/libcontainer/cgroups"
+)
+
+// containerCgroupsFile is the file in the supervisor's state directory that records
+// the containers started by the supervisor which have not been deleted, their
+// cgroups are left behind if the supervisor exits before the containers do
+const containerCgroupsFile = "container-cgroups.json"
+
+// WithCgroupReaping enables a sweep at startup that removes empty cgroups left
+// behind by containers that no longer exist.  Only the direct children of parent
+// are considered, if parent is empty containerd's own cgroup is used as this is
+// where the runtime creates a container's cgroups when its spec does not set a
+// cgroups path.  Only the cgroups named after containers that were started by the
+// supervisor and never deleted are removed so that other cgroups under the parent
+// are left alone.
+func WithCgroupReaping(parent string) Option {
+	return func(s *Supervisor) {
+		s.cgroupReaping = true
+		s.cgroupParent = parent
+	}
+}
+
+func loadContainerCgroups(stateDir string) (map[string]bool, error) {
+	ids := make(map[string]bool)
+	if err := readStateFile(filepath.Join(stateDir, containerCgroupsFile), &ids); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (s *Supervisor) saveContainerCgroups() {
+	if err := writeStateFile(filepath.Join(s.stateDir, containerCgroupsFile), s.containerCgroups, len(s.containerCgroups) == 0); err != nil {
+		logrus.WithField("error", err).Error("containerd: save container cgroups")
+	}
+}
+
+// recordContainerCgroup records that the container's cgroups may need to be reaped
+// if the container is not deleted by the supervisor
+func (s *Supervisor) recordContainerCgroup(id string) {
+	if s.containerCgroups[id] {
+		return
+	}
+	s.containerCgroups[id] = true
+	s.saveContainerCgroups()
+}
+
+// forgetContainerCgroup removes the record of a container whose cgroups were
+// removed by the runtime when it was deleted
+func (s *Supervisor) forgetContainerCgroup(id string) {
+	if !s.containerCgroups[id] {
+		return
+	}
+	delete(s.containerCgroups, id)
+	s.saveContainerCgroups()
+}
+
+// reapCgroups removes the cgroups under the cgroup parent in every hierarchy that
+// are named after a container that was started by the supervisor, was not deleted
+// and was not restored, and have no processes or child cgroups.  The kernel
+// refuses to remove a cgroup that is still in use so a cgroup that becomes
+// populated during the sweep is left alone and is considered again on the next
+// startup.
+func (s *Supervisor) reapCgroups() {
+	mounts, err := cgroups.GetCgroupMounts()
+	if err != nil {
+		logrus.WithField("error", err).Error("containerd: find cgroup mounts")
+		return
+	}
+	var (
+		removed   = 0
+		remaining = make(map[string]bool)
+	)
+	for _, m := range mounts {
+		if len(m.Subsystems) == 0 {
+			continue
+		}
+		dir, err := s.cgroupParentDir(m)
+		if err != nil {
+			logrus.WithFields(logrus.Fields{
+				"error":     err,
+				"subsystem": m.Subsystems[0],
+			}).Warn("containerd: find cgroup parent")
+			continue
+		}
+		dirs, err := ioutil.ReadDir(dir)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				logrus.WithFields(logrus.Fields{
+					"error": err,
+					"path":  dir,
+				}).Warn("containerd: read cgroup parent")
+			}
+			continue
+		}
+		for _, d := range dirs {
+			if !d.IsDir() {
+				continue
+			}
+			if _, ok := s.containers[d.Name()]; ok || !s.containerCgroups[d.Name()] {
+				continue
+			}
+			path := filepath.Join(dir, d.Name())
+			if !s.isOrphanedCgroup(path) {
+				remaining[d.Name()] = true
+				continue
+			}
+			if err := os.Remove(path); err != nil {
+				logrus.WithFields(logrus.Fields{
+					"error": err,
+					"path":  path,
+				}).Debug("containerd: remove orphaned cgroup")
+				remaining[d.Name()] = true
+				continue
+			}
+			logrus.WithField("path", path).Debug("containerd: removed orphaned cgroup")
+			removed++
+		}
+	}
+	if removed > 0 {
+		logrus.WithField("count", removed).Info("containerd: removed orphaned cgroups")
+	}
+	changed := false
+	for id := range s.containerCgroups {
+		if _, ok := s.containers[id]; !ok && !remaining[id] {
+			delete(s.containerCgroups, id)
+			changed = true
+		}
+	}
+	if changed {
+		s.saveContainerCgroups()
+	}
+}
+
+// cgroupParentDir returns the path of the cgroup parent on the mount m
+func (s *Supervisor) cgroupParentDir(m cgroups.Mount) (string, error) {
+	parent := s.cgroupParent
+	if parent == "" {
+		var err error
+		if parent, err = cgroups.GetThisCgroupDir(m.Subsystems[0]); err != nil {
+			return "", err
+		}
+	}
+	rel, err := filepath.Rel(m.Root, filepath.Join("/", parent))
+	if err != nil {
+		return "", err
+	}
+	if strings.HasPrefix(rel, "..") {
+		return "", os.ErrNotExist
+	}
+	return filepath.Join(m.Mountpoint, rel), nil
+}
+
+// isOrphanedCgroup returns true if the cgroup at path has no processes and no
+// child cgroups
+func (s *Supervisor) isOrphanedCgroup(path string) bool {
+	pids, err := cgroups.GetPids(path)
+	if err != nil || len(pids) > 0 {
+		return false
+	}
+	dirs, err := ioutil.ReadDir(path)
+	if err != nil {
+		return false
+	}
+	for _, d := range dirs {
+		if d.IsDir() {
+			return false
+		}
+	}
+	return true
+}
+
+// CgroupControllers are the cgroup v2 controllers enabled for a container's cgroup
+type CgroupControllers struct {
+	// Path is the container's cgroup relative to the cgroup v2 mount
+	Path string
+	// Controllers are the controllers enabled for the container's cgroup
+	Controllers []string
+	// SubtreeControl holds the controllers each ancestor of the container's cgroup
+	// enables for its children, keyed by the ancestor's path
+	SubtreeControl map[string][]string
+}
+
+type CgroupControllersTask struct {
+	s *Supervisor
+}
+
+// Handle returns the cgroup v2 controllers of the cgroup of the container's init
+// process.
+func (h *CgroupControllersTask) Handle(e *Task) error {
+	i, ok := h.s.containers[e.ID]
+	if !ok {
+		return ErrContainerNotFound
+	}
+	processes, err := i.container.Processes()
+	if err != nil {
+		return err
+	}
+	for _, p := range processes {
+		if p.ID() != runtime.InitProcessID {
+			continue
+		}
+		c, err := cgroupControllers(p.SystemPid())
+		if err != nil {
+			return err
+		}
+		e.CgroupControllers = c
+		return nil
+	}
+	return ErrProcessNotFound
+}
+
+func cgroupControllers(pid int) (*CgroupControllers, error) {
+	root, err := cgroup2Mountpoint()
+	if err != nil {
+		return nil, err
+	}
+	path, err := cgroup2Path(pid)
+	if err != nil {
+		return nil, err
+	}
+	controllers, err := readControllers(filepath.Join(root, path, "cgroup.controllers"))
+	if err != nil {
+		return nil, err
+	}
+	c := &CgroupControllers{
+		Path:           path,
+		Controllers:    controllers,
+		SubtreeControl: make(map[string][]string),
+	}
+	for p := filepath.Dir(path); ; p = filepath.Dir(p) {
+		if c.SubtreeControl[p], err = readControllers(filepath.Join(root, p, "cgroup.subtree_control")); err != nil {
+			return nil, err
+		}
+		if p == "/" {
+			break
+		}
+	}
+	return c, nil
+}
+
+func cgroup2Mountpoint() (string, error) {
+	mounts, err := mount.GetMounts()
+	if err != nil {
+		return "", err
+	}
+	for _, m := range mounts {
+		if m.Fstype == "cgroup2" {
+			return m.Mountpoint, nil
+		}
+	}
+	return "", ErrCgroupV2NotMounted
+}
+
+// cgroup2Path returns the cgroup v2 path of the process from the "0::" entry
+// of /proc/<pid>/cgroup
+func cgroup2Path(pid int) (string, error) {
+	data, err := ioutil.ReadFile(fmt.Sprintf("/proc/%d/cgroup", pid))
+	if err != nil {
+		return "", err
+	}
+	for _, l := range strings.Split(string(data), "\n") {
+		if strings.HasPrefix(l, "0::") {
+			return l[len("0::"):], nil
+		}
+	}
+	return "", ErrCgroupV2NotMounted
+}
+
+func readControllers(path string) ([]string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(string(data)), nil
+}
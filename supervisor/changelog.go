@@ -0,0 +1,74 @@
+package supervisor
+
+import "github.com/docker/containerd/runtime"
+
+// StateTransition is a change in a container's state derived from the event log.
+// Seq is the sequence number of the event that caused the transition.
+type StateTransition struct {
+	ID   string
+	From runtime.State
+	To   runtime.State
+	Seq  uint64
+}
+
+// lifecycleStates are the states that containers enter on lifecycle events
+var lifecycleStates = map[string]runtime.State{
+	"start-container": runtime.Running,
+	"pause":           runtime.Paused,
+	"resume":          runtime.Running,
+	"exit":            runtime.Stopped,
+}
+
+type ChangelogTask struct {
+	s *Supervisor
+}
+
+// Handle returns the container state transitions caused by events from the
+// sequence number e.Seq.  Clients pass the last sequence number they have seen
+// plus one to receive only new transitions.
+func (h *ChangelogTask) Handle(e *Task) error {
+	e.Changelog = h.s.changelog(e.Seq)
+	return nil
+}
+
+func (s *Supervisor) changelog(first uint64) []StateTransition {
+	s.eventLock.RLock()
+	defer s.eventLock.RUnlock()
+	var (
+		out    []StateTransition
+		states = make(map[string]runtime.State)
+	)
+	// the whole log is replayed so that the state before the first returned
+	// transition is known
+	for _, e := range s.eventLog {
+		to, ok := lifecycleStates[e.Type]
+		if !ok {
+			continue
+		}
+		// only the exit of the init process stops the container
+		if e.Type == "exit" && e.Pid != runtime.InitProcessID {
+			continue
+		}
+		from, ok := states[e.ID]
+		if !ok {
+			from = runtime.Created
+		}
+		if from == to {
+			continue
+		}
+		if to == runtime.Stopped {
+			delete(states, e.ID)
+		} else {
+			states[e.ID] = to
+		}
+		if e.Seq >= first {
+			out = append(out, StateTransition{
+				ID:   e.ID,
+				From: from,
+				To:   to,
+				Seq:  e.Seq,
+			})
+		}
+	}
+	return out
+}
@@ -1,15 +1,191 @@
 package supervisor
 
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/docker/containerd/runtime"
+)
+
+// CheckpointPolicy controls how a checkpoint is created when a checkpoint with the
+// same name already exists for the container
+type CheckpointPolicy string
+
+const (
+	// CheckpointFail fails with runtime.ErrCheckpointExists
+	CheckpointFail CheckpointPolicy = ""
+	// CheckpointOverwrite replaces the existing checkpoint, the existing checkpoint
+	// is kept if the new checkpoint fails
+	CheckpointOverwrite CheckpointPolicy = "overwrite"
+	// CheckpointSkipExisting returns the existing checkpoint in the task
+	CheckpointSkipExisting CheckpointPolicy = "skipExisting"
+)
+
+// validateCheckpointName returns ErrInvalidCheckpointName if the checkpoint name
+// could resolve outside of the container's checkpoint directory.  Names starting
+// with a dot are used by the runtime for checkpoints that are being created.
+func validateCheckpointName(name string) error {
+	if name == "" || strings.HasPrefix(name, ".") || strings.ContainsAny(name, `/\`) || !utf8.ValidString(name) {
+		return ErrInvalidCheckpointName
+	}
+	for _, r := range name {
+		if unicode.IsControl(r) || unicode.Is(unicode.Cf, r) {
+			return ErrInvalidCheckpointName
+		}
+	}
+	return nil
+}
+
+// WithCheckpointRoots sets the directories that checkpoints can be stored under
+// when a checkpoint has a path.  By default checkpoints can only be stored in the
+// checkpoints directory of the container's bundle.
+func WithCheckpointRoots(roots ...string) Option {
+	return func(s *Supervisor) {
+		s.checkpointRoots = roots
+	}
+}
+
+// validateCheckpointPath returns ErrInvalidCheckpointPath if the directory that a
+// checkpoint is stored in is not a clean absolute path to an existing directory
+// under one of the checkpoint roots once symlinks are resolved.  An empty path is
+// the container's checkpoints directory.
+func (s *Supervisor) validateCheckpointPath(path string) error {
+	if path == "" {
+		return nil
+	}
+	if !filepath.IsAbs(path) || filepath.Clean(path) != path {
+		return ErrInvalidCheckpointPath
+	}
+	fi, err := os.Stat(path)
+	if err != nil || !fi.IsDir() {
+		return ErrInvalidCheckpointPath
+	}
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return ErrInvalidCheckpointPath
+	}
+	for _, root := range s.checkpointRoots {
+		if root, err = filepath.EvalSymlinks(root); err != nil {
+			continue
+		}
+		if rel, err := filepath.Rel(root, resolved); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return nil
+		}
+	}
+	return ErrInvalidCheckpointPath
+}
+
 type CreateCheckpointTask struct {
 	s *Supervisor
 }
 
+// Handle creates the checkpoint and emits a checkpoint-progress event as each phase
+// starts followed by a checkpoint event once the checkpoint is complete.  The runtime
+// does not report the pages dumped by CRIU so progress is reported by phase.  The
+// checkpoint is not started if the task's context is done and the dump is killed if
+// the context is done before it completes.
 func (h *CreateCheckpointTask) Handle(e *Task) error {
+	if err := validateCheckpointName(e.Checkpoint.Name); err != nil {
+		return err
+	}
+	if err := h.s.validateCheckpointPath(e.Checkpoint.Path); err != nil {
+		return err
+	}
+	i, ok := h.s.containers[e.ID]
+	if !ok {
+		return ErrContainerNotFound
+	}
+	if err := e.Context().Err(); err != nil {
+		return err
+	}
+	if e.CheckpointPolicy == CheckpointSkipExisting {
+		cpt, err := findCheckpoint(i.container, *e.Checkpoint)
+		if err != nil {
+			return err
+		}
+		if cpt != nil {
+			*e.Checkpoint = *cpt
+			return nil
+		}
+	}
+	checkpoint := i.container.Checkpoint
+	if e.CheckpointPolicy == CheckpointOverwrite {
+		checkpoint = i.container.ReplaceCheckpoint
+	}
+	if err := checkpoint(*e.Checkpoint, runtime.CheckpointOpts{
+		Progress: func(phase string) {
+			h.s.notifySubscribers(withCorrelationID(Event{
+				ID:        e.ID,
+				Type:      "checkpoint-progress",
+				Timestamp: time.Now(),
+				Metadata: map[string]string{
+					"checkpoint": e.Checkpoint.Name,
+					"phase":      phase,
+				},
+			}, e.CorrelationID))
+		},
+		Cancel: e.Context().Done(),
+	}); err != nil {
+		if err == runtime.ErrCanceled {
+			return e.Context().Err()
+		}
+		return err
+	}
+	h.s.notifySubscribers(withCorrelationID(Event{
+		ID:        e.ID,
+		Type:      "checkpoint",
+		Timestamp: time.Now(),
+		Metadata: map[string]string{
+			"checkpoint": e.Checkpoint.Name,
+		},
+	}, e.CorrelationID))
+	return nil
+}
+
+// findCheckpoint returns the container's checkpoint with the same name and path as
+// the provided checkpoint or nil if it does not exist
+func findCheckpoint(c runtime.Container, checkpoint runtime.Checkpoint) (*runtime.Checkpoint, error) {
+	checkpoints, err := c.Checkpoints(checkpoint.Path)
+	if err != nil {
+		return nil, err
+	}
+	for _, cpt := range checkpoints {
+		if cpt.Name == checkpoint.Name {
+			return &cpt, nil
+		}
+	}
+	return nil, nil
+}
+
+type ListCheckpointsTask struct {
+	s *Supervisor
+}
+
+// Handle returns the checkpoints of the container e.ID in e.Checkpoints, oldest
+// first.  The checkpoints are listed from e.Checkpoint.Path when the task has a
+// checkpoint.
+func (h *ListCheckpointsTask) Handle(e *Task) error {
+	var path string
+	if e.Checkpoint != nil {
+		path = e.Checkpoint.Path
+	}
+	if err := h.s.validateCheckpointPath(path); err != nil {
+		return err
+	}
 	i, ok := h.s.containers[e.ID]
 	if !ok {
 		return ErrContainerNotFound
 	}
-	return i.container.Checkpoint(*e.Checkpoint)
+	checkpoints, err := i.container.Checkpoints(path)
+	if err != nil {
+		return err
+	}
+	e.Checkpoints = checkpoints
+	return nil
 }
 
 type DeleteCheckpointTask struct {
@@ -17,9 +193,15 @@ type DeleteCheckpointTask struct {
 }
 
 func (h *DeleteCheckpointTask) Handle(e *Task) error {
+	if err := validateCheckpointName(e.Checkpoint.Name); err != nil {
+		return err
+	}
+	if err := h.s.validateCheckpointPath(e.Checkpoint.Path); err != nil {
+		return err
+	}
 	i, ok := h.s.containers[e.ID]
 	if !ok {
 		return ErrContainerNotFound
 	}
-	return i.container.DeleteCheckpoint(e.Checkpoint.Name)
+	return i.container.DeleteCheckpoint(e.Checkpoint.Name, e.Checkpoint.Path)
 }
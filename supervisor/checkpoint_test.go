@@ -0,0 +1,136 @@
+package supervisor
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/containerd/runtime"
+)
+
+func TestValidateCheckpointName(t *testing.T) {
+	for _, name := range []string{
+		"checkpoint",
+		"checkpoint-1.tar",
+		"ckpt..1",
+		"контрольная-точка",
+		"チェックポイント",
+		"\u2024\u2024",
+	} {
+		if err := validateCheckpointName(name); err != nil {
+			t.Errorf("expected %q to be valid but received %v", name, err)
+		}
+	}
+	for _, name := range []string{
+		"",
+		".",
+		"..",
+		".hidden",
+		"../../etc",
+		"a/../../etc",
+		"/etc/passwd",
+		"a/b",
+		`..\..\etc`,
+		"a\x00b",
+		"a\nb",
+		"a\x7fb",
+		"a\u0085b",
+		"a\u200bb",
+		"a\u202eb",
+		"a\xffb",
+	} {
+		if err := validateCheckpointName(name); err != ErrInvalidCheckpointName {
+			t.Errorf("expected %q to be invalid but received %v", name, err)
+		}
+	}
+}
+
+func TestCheckpointTasksRejectInvalidNames(t *testing.T) {
+	s := &Supervisor{
+		containers: make(map[string]*containerInfo),
+	}
+	for _, h := range []Handler{
+		&CreateCheckpointTask{s},
+		&DeleteCheckpointTask{s},
+	} {
+		e := &Task{
+			ID:         "test",
+			Checkpoint: &runtime.Checkpoint{Name: "../../etc"},
+		}
+		if err := h.Handle(e); err != ErrInvalidCheckpointName {
+			t.Errorf("expected %T to fail with ErrInvalidCheckpointName but received %v", h, err)
+		}
+	}
+}
+
+func TestValidateCheckpointPath(t *testing.T) {
+	dir, err := ioutil.TempDir("", "containerd-checkpoint-path")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	root := filepath.Join(dir, "root")
+	for _, d := range []string{filepath.Join(root, "shared"), filepath.Join(dir, "other")} {
+		if err := os.MkdirAll(d, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	file := filepath.Join(root, "file")
+	if err := ioutil.WriteFile(file, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	// a symlink under the root that escapes it
+	if err := os.Symlink(filepath.Join(dir, "other"), filepath.Join(root, "link")); err != nil {
+		t.Fatal(err)
+	}
+	s := &Supervisor{checkpointRoots: []string{root}}
+	for _, path := range []string{"", root, filepath.Join(root, "shared")} {
+		if err := s.validateCheckpointPath(path); err != nil {
+			t.Errorf("expected %q to be valid but received %v", path, err)
+		}
+	}
+	for _, path := range []string{
+		"checkpoints",
+		root + "/",
+		root + "/../" + filepath.Base(root),
+		filepath.Join(root, "missing"),
+		file,
+		filepath.Join(dir, "other"),
+		filepath.Join(root, "link"),
+	} {
+		if err := s.validateCheckpointPath(path); err != ErrInvalidCheckpointPath {
+			t.Errorf("expected %q to be invalid but received %v", path, err)
+		}
+	}
+}
+
+func TestLoadStartCheckpoint(t *testing.T) {
+	bundle, err := ioutil.TempDir("", "containerd-bundle")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(bundle)
+	dir := filepath.Join(bundle, "checkpoints", "test")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "config.json"), []byte(`{"name":"test","tcp":true}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	s := &Supervisor{}
+	e := &Task{
+		BundlePath: bundle,
+		Checkpoint: &runtime.Checkpoint{Name: "missing"},
+	}
+	if err := s.loadStartCheckpoint(e); err != runtime.ErrCheckpointNotExists {
+		t.Fatalf("expected ErrCheckpointNotExists but received %v", err)
+	}
+	e.Checkpoint = &runtime.Checkpoint{Name: "test"}
+	if err := s.loadStartCheckpoint(e); err != nil {
+		t.Fatal(err)
+	}
+	if !e.Checkpoint.Tcp {
+		t.Fatal("expected the checkpoint flags to be loaded for the restore")
+	}
+}
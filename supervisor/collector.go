@@ -0,0 +1,195 @@
+package supervisor
+
+import (
+	"sync"
+	"time"
+
+	"github.com/docker/containerd/runtime"
+)
+
+const statsHistorySize = 60 // number of samples kept per container
+
+// WithStatsInterval sets the default interval used by the background stats
+// collector for containers that do not specify their own interval.  A zero
+// interval disables background collection for those containers.
+func WithStatsInterval(d time.Duration) Option {
+	return func(s *Supervisor) {
+		s.collector.interval = d
+	}
+}
+
+// WithStatsConcurrency limits the number of containers whose stats are collected
+// in the background at the same time.  Zero means unlimited.
+func WithStatsConcurrency(n int) Option {
+	return func(s *Supervisor) {
+		if n > 0 {
+			s.collector.slots = make(chan struct{}, n)
+		}
+	}
+}
+
+// statsCollector samples container stats in the background.  Each container is
+// scheduled independently so that containers can be sampled at different intervals.
+type statsCollector struct {
+	m          sync.Mutex
+	interval   time.Duration
+	containers map[string]*collectedContainer
+	// peaks holds the highest memory usage seen for each container
+	peaks map[string]uint64
+	// slots bounds the number of concurrent collections, nil is unbounded
+	slots chan struct{}
+}
+
+type collectedContainer struct {
+	container runtime.Container
+	interval  time.Duration
+	done      chan struct{}
+	history   []*runtime.Stat
+}
+
+func newStatsCollector() *statsCollector {
+	return &statsCollector{
+		containers: make(map[string]*collectedContainer),
+		peaks:      make(map[string]uint64),
+	}
+}
+
+// add starts collecting stats for the container every interval.  If interval
+// is zero the collector's default interval is used.
+func (c *statsCollector) add(container runtime.Container, interval time.Duration) {
+	if interval == 0 {
+		interval = c.interval
+	}
+	if interval <= 0 {
+		return
+	}
+	c.m.Lock()
+	defer c.m.Unlock()
+	if _, ok := c.containers[container.ID()]; ok {
+		return
+	}
+	cc := &collectedContainer{
+		container: container,
+		interval:  interval,
+		done:      make(chan struct{}),
+	}
+	c.containers[container.ID()] = cc
+	go c.collect(cc)
+}
+
+// remove stops collecting stats for the container with the provided id
+func (c *statsCollector) remove(id string) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	if cc, ok := c.containers[id]; ok {
+		close(cc.done)
+		delete(c.containers, id)
+	}
+	delete(c.peaks, id)
+}
+
+// observe records the peak memory usage of the container from the stats
+func (c *statsCollector) observe(id string, st *runtime.Stat) {
+	lst := st.Raw
+	if lst == nil || lst.CgroupStats == nil {
+		return
+	}
+	// cgroup v1 tracks the peak in max_usage, otherwise only the current usage is known
+	mem := lst.CgroupStats.MemoryStats.Usage
+	peak := mem.MaxUsage
+	if mem.Usage > peak {
+		peak = mem.Usage
+	}
+	c.m.Lock()
+	if peak > c.peaks[id] {
+		c.peaks[id] = peak
+	}
+	c.m.Unlock()
+}
+
+// history returns the collected stats for the container, oldest first
+func (c *statsCollector) history(id string) []*runtime.Stat {
+	c.m.Lock()
+	defer c.m.Unlock()
+	cc, ok := c.containers[id]
+	if !ok {
+		return nil
+	}
+	out := make([]*runtime.Stat, len(cc.history))
+	copy(out, cc.history)
+	return out
+}
+
+func (c *statsCollector) collect(cc *collectedContainer) {
+	t := time.NewTicker(cc.interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-cc.done:
+			return
+		case <-t.C:
+			if !c.sample(cc) {
+				return
+			}
+			// a tick received while sampling is skipped so that a container that
+			// is slow to sample is not sampled back to back
+			select {
+			case <-t.C:
+				containerLog(cc.container.ID()).Debug("containerd: skip stats collection, previous collection did not finish")
+			default:
+			}
+		}
+	}
+}
+
+// sample collects the stats of the container once a slot is free, it returns false
+// if the container was removed while waiting
+func (c *statsCollector) sample(cc *collectedContainer) (ok bool) {
+	if c.slots != nil {
+		select {
+		case c.slots <- struct{}{}:
+		case <-cc.done:
+			return false
+		}
+		defer func() {
+			<-c.slots
+		}()
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			statsPanic(cc.container.ID(), r)
+			ok = true
+		}
+	}()
+	start := time.Now()
+	st, err := cc.container.Stats()
+	if err != nil {
+		containerLog(cc.container.ID()).WithField("error", err).Debug("containerd: collect container stats")
+		return true
+	}
+	ContainerStatsTimer.UpdateSince(start)
+	c.observe(cc.container.ID(), st)
+	c.m.Lock()
+	cc.history = append(cc.history, st)
+	if len(cc.history) > statsHistorySize {
+		cc.history = cc.history[len(cc.history)-statsHistorySize:]
+	}
+	c.m.Unlock()
+	return true
+}
+
+// StatsHistory returns the stats collected in the background for the container
+// with the provided id, oldest first.
+func (s *Supervisor) StatsHistory(id string) []*runtime.Stat {
+	return s.collector.history(id)
+}
+
+// PeakMemory returns the highest memory usage in bytes seen for the container with
+// the provided id since it was started.  Usage is seen by background stats collection
+// and stats requests, on cgroup v1 the kernel's own peak usage is included.
+func (s *Supervisor) PeakMemory(id string) (uint64, bool) {
+	s.collector.m.Lock()
+	defer s.collector.m.Unlock()
+	peak, ok := s.collector.peaks[id]
+	return peak, ok
+}
@@ -0,0 +1,96 @@
+package supervisor
+
+import (
+	"sort"
+	"time"
+
+	"github.com/docker/containerd/runtime"
+)
+
+// ContainerSnapshot is a copy of a container's state at the time it was taken
+type ContainerSnapshot struct {
+	ID     string
+	State  runtime.State
+	Bundle string
+	// Pids are the system pids of the container's processes
+	Pids []int
+	// InitPid is the system pid of the container's init process, zero until the
+	// container is started
+	InitPid int
+	Created time.Time
+}
+
+// Containers returns snapshots of the supervisor's containers sorted by id.  Unlike
+// a GetContainerTaskType task it does not wait for the event loop.
+func (s *Supervisor) Containers() []ContainerSnapshot {
+	s.trackLock.Lock()
+	out := make([]ContainerSnapshot, 0, len(s.tracked))
+	for _, c := range s.tracked {
+		c := *c
+		c.Pids = append([]int(nil), c.Pids...)
+		out = append(out, c)
+	}
+	s.trackLock.Unlock()
+	sort.Sort(containerSnapshots(out))
+	return out
+}
+
+type containerSnapshots []ContainerSnapshot
+
+func (c containerSnapshots) Len() int {
+	return len(c)
+}
+
+func (c containerSnapshots) Swap(i, j int) {
+	c[i], c[j] = c[j], c[i]
+}
+
+func (c containerSnapshots) Less(i, j int) bool {
+	return c[i].ID < c[j].ID
+}
+
+// addContainer adds the container to the supervisor, it must be called from the
+// event loop
+func (s *Supervisor) addContainer(container runtime.Container, created time.Time) {
+	s.containers[container.ID()] = s.newContainerInfo(container)
+	s.trackLock.Lock()
+	s.tracked[container.ID()] = &ContainerSnapshot{
+		ID:      container.ID(),
+		State:   runtime.Created,
+		Bundle:  container.Path(),
+		Created: created,
+	}
+	s.trackLock.Unlock()
+}
+
+// updateSnapshot updates the snapshot of the container with its processes and the
+// state, an empty state leaves the state unchanged
+func (s *Supervisor) updateSnapshot(container runtime.Container, state runtime.State) {
+	var (
+		pids    []int
+		initPid int
+	)
+	if processes, err := container.Processes(); err == nil {
+		for _, p := range processes {
+			pids = append(pids, p.SystemPid())
+			if p.ID() == runtime.InitProcessID {
+				initPid = p.SystemPid()
+			}
+		}
+	}
+	sort.Ints(pids)
+	s.trackLock.Lock()
+	defer s.trackLock.Unlock()
+	c, ok := s.tracked[container.ID()]
+	if !ok {
+		return
+	}
+	// snapshots are replaced rather than modified so that copies stay immutable
+	n := *c
+	n.Pids = pids
+	n.InitPid = initPid
+	if state != "" {
+		n.State = state
+	}
+	s.tracked[container.ID()] = &n
+}
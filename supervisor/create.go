@@ -1,6 +1,9 @@
 package supervisor
 
 import (
+	"os"
+	"path/filepath"
+	"sync/atomic"
 	"time"
 
 	"github.com/docker/containerd/runtime"
@@ -12,14 +15,136 @@ type StartTask struct {
 
 func (h *StartTask) Handle(e *Task) error {
 	start := time.Now()
-	container, err := runtime.New(h.s.stateDir, e.ID, e.BundlePath, e.Labels)
+	// a retried start must not replace the state or the monitoring of the
+	// container that is already running with the id
+	if _, ok := h.s.containers[e.ID]; ok {
+		return ErrContainerExists
+	}
+	container, err := h.s.createContainer(e)
 	if err != nil {
+		h.s.startFailed(e.ID, e.CorrelationID, err)
 		return err
 	}
-	h.s.containers[e.ID] = &containerInfo{
-		container: container,
+	h.s.tasks <- newStartTask(e, container)
+	ContainerCreateTimer.UpdateSince(start)
+	return errDeferedResponse
+}
+
+// createContainer creates the state for the container described by the start task
+// and adds it to the supervisor
+func (s *Supervisor) createContainer(e *Task) (container runtime.Container, err error) {
+	// the tasks channel is closed once the supervisor is stopped
+	if atomic.LoadInt32(&s.stopping) == 1 {
+		return nil, errShutdown
+	}
+	if s.DiskFull() {
+		return nil, ErrDiskFull
+	}
+	if err := s.validateRuntimeArgs(e.RuntimeArgs); err != nil {
+		return nil, err
+	}
+	if e.Checkpoint != nil {
+		if err := s.loadStartCheckpoint(e); err != nil {
+			return nil, err
+		}
+	}
+	runtimeBinary, err := s.containerRuntime(e)
+	if err != nil {
+		return nil, err
+	}
+	initWrapper, err := s.containerInitWrapper(e)
+	if err != nil {
+		return nil, err
+	}
+	stateDir := s.stateDir
+	if e.StateDir != "" {
+		if !filepath.IsAbs(e.StateDir) {
+			return nil, ErrStateDirNotAbs
+		}
+		if err := os.MkdirAll(e.StateDir, 0755); err != nil {
+			return nil, err
+		}
+		stateDir = e.StateDir
+	}
+	if e.Snapshot != "" {
+		if err := s.mountSnapshot(e.ID, e.BundlePath, e.Snapshot); err != nil {
+			return nil, err
+		}
+		defer func() {
+			if err != nil {
+				if rerr := s.releaseSnapshot(e.ID); rerr != nil {
+					containerLog(e.ID).WithField("error", rerr).Error("containerd: release snapshot")
+				}
+			}
+		}()
+	}
+	container, err = runtime.New(stateDir, e.ID, e.BundlePath, e.Labels, runtimeBinary, e.RuntimeArgs, initWrapper)
+	if err != nil {
+		if isNoSpace(err) {
+			s.setDiskFull(true, runtime.StateFile)
+		}
+		return nil, err
+	}
+	if s.specPolicy != nil {
+		spec, err := container.Spec()
+		if err == nil {
+			err = s.evaluateSpec(e.ID, spec)
+		}
+		if err != nil {
+			container.Delete()
+			return nil, err
+		}
 	}
+	if stateDir != s.stateDir {
+		s.stateDirs[e.ID] = stateDir
+		if err := s.saveStateDirs(); err != nil {
+			delete(s.stateDirs, e.ID)
+			container.Delete()
+			return nil, err
+		}
+	}
+	// the start order is recorded so that it survives a restart of the supervisor
+	s.containerSeq++
+	s.stopConfigs[e.ID] = stopConfig{
+		Policy:   e.StopPolicy,
+		Priority: e.StopPriority,
+		Seq:      s.containerSeq,
+	}
+	if err := s.saveStopConfigs(); err != nil {
+		containerLog(e.ID).WithField("error", err).Error("containerd: save container stop policies")
+	}
+	s.addContainer(container, time.Now())
+	s.recordContainerCgroup(e.ID)
 	ContainersCounter.Inc(1)
+	return container, nil
+}
+
+// loadStartCheckpoint replaces the checkpoint of a start task, which only needs a
+// name, with the checkpoint restored by the container so that the init process
+// reconnects tcp connections and unix sockets as the checkpoint was created.
+// It returns runtime.ErrCheckpointNotExists if the checkpoint does not exist.
+func (s *Supervisor) loadStartCheckpoint(e *Task) error {
+	if err := validateCheckpointName(e.Checkpoint.Name); err != nil {
+		return err
+	}
+	if err := s.validateCheckpointPath(e.Checkpoint.Path); err != nil {
+		return err
+	}
+	dir := e.Checkpoint.Path
+	if dir == "" {
+		dir = filepath.Join(e.BundlePath, "checkpoints")
+	}
+	cpt, err := runtime.LoadCheckpoint(dir, e.Checkpoint.Name)
+	if err != nil {
+		return err
+	}
+	cpt.Path = e.Checkpoint.Path
+	e.Checkpoint = cpt
+	return nil
+}
+
+// newStartTask returns the task for a worker to start the container's init process
+func newStartTask(e *Task, container runtime.Container) *startTask {
 	task := &startTask{
 		Err:           e.Err,
 		Container:     container,
@@ -27,11 +152,11 @@ func (h *StartTask) Handle(e *Task) error {
 		Stdin:         e.Stdin,
 		Stdout:        e.Stdout,
 		Stderr:        e.Stderr,
+		StatsInterval: e.StatsInterval,
+		CorrelationID: e.CorrelationID,
 	}
 	if e.Checkpoint != nil {
-		task.Checkpoint = e.Checkpoint.Name
+		task.Checkpoint = e.Checkpoint
 	}
-	h.s.tasks <- task
-	ContainerCreateTimer.UpdateSince(start)
-	return errDeferedResponse
+	return task
 }
@@ -0,0 +1,54 @@
+package supervisor
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStartTaskRejectsExistingID(t *testing.T) {
+	dir, err := ioutil.TempDir("", "containerd-start")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	s, err := New(filepath.Join(dir, "state"), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Start()
+	defer s.Close()
+	h := &StartTask{s}
+	e := NewTask(StartContainerTaskType)
+	e.ID = "test"
+	e.BundlePath = filepath.Join(dir, "bundle")
+	if err := h.Handle(e); err != errDeferedResponse {
+		t.Fatalf("expected the first start to be handed to a worker but received %v", err)
+	}
+	original := s.containers["test"]
+	// a state directory of its own lets the second start get as far as replacing
+	// the container if the id is not checked
+	retry := NewTask(StartContainerTaskType)
+	retry.ID = "test"
+	retry.BundlePath = filepath.Join(dir, "other")
+	retry.StateDir = filepath.Join(dir, "other-state")
+	if err := h.Handle(retry); err != ErrContainerExists {
+		t.Fatalf("expected ErrContainerExists but received %v", err)
+	}
+	if s.containers["test"] != original {
+		t.Fatal("expected the original container to be untouched")
+	}
+	if bundle := original.container.Path(); bundle != e.BundlePath {
+		t.Fatalf("expected bundle %q but received %q", e.BundlePath, bundle)
+	}
+	if n := len(s.tasks); n != 1 {
+		t.Fatalf("expected 1 container to be started but received %d", n)
+	}
+	if _, err := os.Stat(retry.StateDir); !os.IsNotExist(err) {
+		t.Fatalf("expected no state for the second start but received %v", err)
+	}
+	if failures := s.StartFailures("test"); len(failures) != 0 {
+		t.Fatalf("expected no start failures but received %v", failures)
+	}
+}
@@ -1,6 +1,9 @@
 package supervisor
 
 import (
+	"strings"
+	"sync/atomic"
+	"syscall"
 	"time"
 
 	"github.com/Sirupsen/logrus"
@@ -11,26 +14,145 @@ type DeleteTask struct {
 	s *Supervisor
 }
 
+// Handle deletes the container after its init process exited with the status
+// e.Status.  Clients set e.Wait or e.Force to delete a container once its init
+// process has exited and receive the exit status in e.Status, -1 if the container
+// was never started.
 func (h *DeleteTask) Handle(e *Task) error {
+	if e.Wait || e.Force {
+		return h.waitDelete(e)
+	}
 	if i, ok := h.s.containers[e.ID]; ok {
 		start := time.Now()
+		// the deleting event is sent while the container's state still exists so
+		// that consumers can capture it before it is removed
+		h.s.notifySubscribers(withCorrelationID(Event{
+			Type:      "deleting",
+			Timestamp: time.Now(),
+			ID:        e.ID,
+			Status:    e.Status,
+			Metadata:  finalState(i.container),
+		}, e.CorrelationID))
+		// the exit is delivered before the container's state is removed so that
+		// it is not delivered again if the daemon restarts before it is removed
+		if e.Process == nil || !e.Process.ExitDelivered() {
+			h.s.notifySubscribers(withCorrelationID(Event{
+				Type:      "exit",
+				Timestamp: time.Now(),
+				ID:        e.ID,
+				Status:    e.Status,
+				Pid:       e.Pid,
+			}, e.CorrelationID))
+			if e.Process != nil {
+				markExitDelivered(e.Process)
+			}
+		}
 		if err := h.deleteContainer(i.container); err != nil {
 			logrus.WithField("error", err).Error("containerd: deleting container")
 		}
-		h.s.notifySubscribers(Event{
-			Type:      "exit",
+		h.s.notifySubscribers(withCorrelationID(Event{
+			Type:      "deleted",
 			Timestamp: time.Now(),
 			ID:        e.ID,
-			Status:    e.Status,
-			Pid:       e.Pid,
-		})
+		}, e.CorrelationID))
 		ContainersCounter.Dec(1)
+		atomic.AddInt64(&h.s.containerExits, 1)
 		ContainerDeleteTimer.UpdateSince(start)
+		for _, w := range h.s.deleteWaiters[e.ID] {
+			w.Status = e.Status
+			w.Err <- nil
+		}
+		delete(h.s.deleteWaiters, e.ID)
 	}
 	return nil
 }
 
+// waitDelete responds to the task once the container is deleted after its init
+// process exits, killing the init process first if e.Force is set
+func (h *DeleteTask) waitDelete(e *Task) error {
+	i, ok := h.s.containers[e.ID]
+	if !ok {
+		return ErrContainerNotFound
+	}
+	processes, err := i.container.Processes()
+	if err != nil {
+		return err
+	}
+	var init runtime.Process
+	for _, p := range processes {
+		if p.ID() == runtime.InitProcessID {
+			init = p
+		}
+	}
+	if init == nil {
+		// there is no process to wait for
+		h.Handle(&Task{
+			ID:            e.ID,
+			Status:        -1,
+			CorrelationID: e.CorrelationID,
+		})
+		e.Status = -1
+		return nil
+	}
+	if _, err := init.ExitStatus(); err != nil && e.Force {
+		if err := init.Signal(syscall.SIGKILL); err != nil && err != syscall.ESRCH {
+			return err
+		}
+		containerLog(e.ID).Debug("containerd: killed container to delete it")
+	}
+	h.s.deleteWaiters[e.ID] = append(h.s.deleteWaiters[e.ID], e)
+	return errDeferedResponse
+}
+
+// finalState returns the state of a container that is about to be deleted as event
+// metadata
+func finalState(container runtime.Container) map[string]string {
+	m := map[string]string{
+		"state":  string(container.State()),
+		"bundle": container.Path(),
+	}
+	if labels := container.Labels(); len(labels) > 0 {
+		m["labels"] = strings.Join(labels, ",")
+	}
+	return m
+}
+
+// removeContainer removes a container that was created but never started
+func (s *Supervisor) removeContainer(container runtime.Container) {
+	if err := (&DeleteTask{s}).deleteContainer(container); err != nil {
+		containerLog(container.ID()).WithField("error", err).Error("containerd: deleting container")
+	}
+	ContainersCounter.Dec(1)
+}
+
 func (h *DeleteTask) deleteContainer(container runtime.Container) error {
+	if ci, ok := h.s.containers[container.ID()]; ok {
+		// wait for the execs in progress, later execs fail as the container
+		// is not found
+		ci.execLock.Lock()
+		ci.deleted = true
+		ci.execLock.Unlock()
+	}
 	delete(h.s.containers, container.ID())
+	h.s.untrackContainer(container.ID())
+	h.s.collector.remove(container.ID())
+	ResetContainerLogLevel(container.ID())
+	if _, ok := h.s.stateDirs[container.ID()]; ok {
+		delete(h.s.stateDirs, container.ID())
+		if err := h.s.saveStateDirs(); err != nil {
+			logrus.WithField("error", err).Error("containerd: save container state directories")
+		}
+	}
+	if _, ok := h.s.stopConfigs[container.ID()]; ok {
+		delete(h.s.stopConfigs, container.ID())
+		if err := h.s.saveStopConfigs(); err != nil {
+			logrus.WithField("error", err).Error("containerd: save container stop policies")
+		}
+	}
+	h.s.forgetContainerCgroup(container.ID())
+	h.s.unwatchMemoryPressure(container.ID())
+	if err := h.s.releaseSnapshot(container.ID()); err != nil {
+		logrus.WithField("error", err).Error("containerd: release container snapshot")
+	}
 	return container.Delete()
 }
@@ -0,0 +1,63 @@
+package supervisor
+
+import (
+	"os"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+)
+
+const defaultDiskFullRetryInterval = 5 * time.Second
+
+// WithDiskFullRetryInterval sets the interval at which writes to the event journal are
+// retried while the state directory is out of space.
+func WithDiskFullRetryInterval(d time.Duration) Option {
+	return func(s *Supervisor) {
+		s.diskFullRetryInterval = d
+	}
+}
+
+// isNoSpace returns true if the error was caused by the filesystem being full
+func isNoSpace(err error) bool {
+	switch e := err.(type) {
+	case *os.PathError:
+		err = e.Err
+	case *os.SyscallError:
+		err = e.Err
+	}
+	return err == syscall.ENOSPC
+}
+
+// DiskFull returns true if the supervisor is in its protective mode because writes to
+// the state directory failed with ENOSPC.  While the disk is full new containers are
+// not started but existing containers keep running.
+func (s *Supervisor) DiskFull() bool {
+	return atomic.LoadInt32(&s.diskFull) == 1
+}
+
+// setDiskFull enters or leaves the disk full mode and emits an event on each transition.
+// source is the file whose write failed.
+func (s *Supervisor) setDiskFull(full bool, source string) {
+	if !full {
+		if atomic.CompareAndSwapInt32(&s.diskFull, 1, 0) {
+			logrus.Info("containerd: state directory writes recovered")
+			s.notifySubscribers(Event{
+				Type:      "disk-full-cleared",
+				Timestamp: time.Now(),
+			})
+		}
+		return
+	}
+	if atomic.CompareAndSwapInt32(&s.diskFull, 0, 1) {
+		logrus.WithField("file", source).Error("containerd: state directory is full, rejecting new containers")
+		s.notifySubscribers(Event{
+			Type:      "disk-full",
+			Timestamp: time.Now(),
+			Metadata: map[string]string{
+				"file": source,
+			},
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package supervisor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/docker/containerd/runtime"
+	"github.com/docker/docker/pkg/mount"
+)
+
+// DiskUsage is the disk usage of a container's writable rootfs directory
+type DiskUsage struct {
+	// Path is the directory that was measured
+	Path string
+	// Bytes is the number of bytes allocated on disk
+	Bytes int64
+	// Inodes is the number of unique inodes
+	Inodes int64
+}
+
+type DiskUsageTask struct {
+	s *Supervisor
+}
+
+// Handle computes the disk usage outside of the event loop because walking
+// the rootfs can take a long time.
+func (h *DiskUsageTask) Handle(e *Task) error {
+	start := time.Now()
+	i, ok := h.s.containers[e.ID]
+	if !ok {
+		return ErrContainerNotFound
+	}
+	go func() {
+		u, err := diskUsage(i.container)
+		if err != nil {
+			e.Err <- err
+			return
+		}
+		e.Err <- nil
+		e.DiskUsage <- u
+		ContainerDiskUsageTimer.UpdateSince(start)
+	}()
+	return errDeferedResponse
+}
+
+func diskUsage(c runtime.Container) (*DiskUsage, error) {
+	dir, err := writableDir(c)
+	if err != nil {
+		return nil, err
+	}
+	u := &DiskUsage{
+		Path: dir,
+	}
+	seen := make(map[uint64]struct{})
+	if err := filepath.Walk(dir, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			// files can be removed by the container while we walk
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		st, ok := fi.Sys().(*syscall.Stat_t)
+		if !ok {
+			return nil
+		}
+		if _, ok := seen[st.Ino]; ok {
+			return nil
+		}
+		seen[st.Ino] = struct{}{}
+		u.Inodes++
+		u.Bytes += st.Blocks * 512
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// writableDir returns the directory holding the writes of the container.  For
+// overlay rootfs mounts this is the upper directory, otherwise it is the rootfs itself.
+func writableDir(c runtime.Container) (string, error) {
+	spec, err := c.Spec()
+	if err != nil {
+		return "", err
+	}
+	root := spec.Root.Path
+	if !filepath.IsAbs(root) {
+		root = filepath.Join(c.Path(), root)
+	}
+	mounts, err := mount.GetMounts()
+	if err != nil {
+		return "", err
+	}
+	for _, m := range mounts {
+		if m.Mountpoint != root || m.Fstype != "overlay" {
+			continue
+		}
+		for _, o := range strings.Split(m.VfsOpts, ",") {
+			if strings.HasPrefix(o, "upperdir=") {
+				return strings.TrimPrefix(o, "upperdir="), nil
+			}
+		}
+	}
+	return root, nil
+}
@@ -10,10 +10,37 @@ var (
 	ErrContainerExists        = errors.New("containerd: container already exists")
 	ErrProcessNotFound        = errors.New("containerd: processs not found for container")
 	ErrUnknownContainerStatus = errors.New("containerd: unknown container status ")
-	ErrUnknownTask            = errors.New("containerd: unknown task type")
+	ErrUnknownTaskType        = errors.New("containerd: unsupported task type")
+	ErrDiskFull               = errors.New("containerd: state directory is full")
+	ErrStateDirNotAbs         = errors.New("containerd: state directory is not an absolute path")
+	ErrInvalidSpoolName       = errors.New("containerd: invalid spool name")
+	ErrInvalidCheckpointName  = errors.New("containerd: invalid checkpoint name")
+	ErrInvalidCheckpointPath  = errors.New("containerd: checkpoint path must be an absolute path to a directory under a checkpoint root")
+	ErrInvalidWindowSize      = errors.New("containerd: invalid terminal window size")
+	ErrSpoolNotFound          = errors.New("containerd: spool not found")
+	ErrTransactionFailed      = errors.New("containerd: transaction failed")
+	ErrRuntimeArgNotAllowed   = errors.New("containerd: runtime argument not allowed")
+	ErrUnknownRuntime         = errors.New("containerd: unknown runtime")
+	ErrCgroupV2NotMounted     = errors.New("containerd: cgroup v2 is not mounted")
+	ErrNotLogFile             = errors.New("containerd: process output is not a file")
+	ErrTooManySubscribers     = errors.New("containerd: too many event subscribers")
+	ErrNoSnapshotter          = errors.New("containerd: no snapshotter configured")
+	ErrStatsQueueFull         = errors.New("containerd: too many stats requests queued")
+	ErrTooManyExecs           = errors.New("containerd: too many exec processes being started")
+	ErrBundleMissing          = errors.New("containerd: container bundle does not exist")
+	ErrUnknownOverflowPolicy  = errors.New("containerd: unknown subscriber overflow policy")
+	ErrNoInitWrapper          = errors.New("containerd: no init wrapper configured")
+	ErrMonitorStopped         = errors.New("containerd: process monitor is not running")
+	ErrWriterNil              = errors.New("containerd: event writer is nil")
+
+	// ErrUnknownTask is the previous name of ErrUnknownTaskType.
+	//
+	// Deprecated: use ErrUnknownTaskType.
+	ErrUnknownTask = ErrUnknownTaskType
 
 	// Internal errors
 	errShutdown          = errors.New("containerd: supervisor is shutdown")
+	errDrainTimeout      = errors.New("containerd: timeout waiting for queued tasks")
 	errRootNotAbs        = errors.New("containerd: rootfs path is not an absolute path")
 	errNoContainerForPid = errors.New("containerd: pid not registered for any container")
 	// internal error where the handler will defer to another for the final response
@@ -0,0 +1,85 @@
+package supervisor
+
+import (
+	"time"
+
+	"github.com/Sirupsen/logrus"
+)
+
+// EventBatches returns a channel that receives events in batches rather than one at a
+// time.  A batch is sent when it holds size events or latency after its first event was
+// received, whichever comes first.  The subscription is otherwise the same as one returned
+// by Events and must be closed with UnsubscribeBatches.
+func (s *Supervisor) EventBatches(from time.Time, size int, latency time.Duration, opts ...EventsOption) (chan []Event, error) {
+	events, err := s.Events(from, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if size < 1 {
+		size = 1
+	}
+	c := make(chan []Event, defaultBufferSize)
+	s.subscriberLock.Lock()
+	s.batchSubscribers[c] = events
+	s.subscriberLock.Unlock()
+	go batchEvents(events, c, size, latency)
+	return c, nil
+}
+
+// UnsubscribeBatches closes a channel returned by EventBatches
+func (s *Supervisor) UnsubscribeBatches(c chan []Event) {
+	s.subscriberLock.Lock()
+	events, ok := s.batchSubscribers[c]
+	delete(s.batchSubscribers, c)
+	s.subscriberLock.Unlock()
+	if ok {
+		s.Unsubscribe(events)
+	}
+}
+
+// batchEvents groups the events into batches sent to c until events is closed
+func batchEvents(events chan Event, c chan []Event, size int, latency time.Duration) {
+	defer close(c)
+	var (
+		batch []Event
+		timer *time.Timer
+		flush <-chan time.Time
+	)
+	send := func() {
+		if timer != nil {
+			timer.Stop()
+			timer, flush = nil, nil
+		}
+		if len(batch) == 0 {
+			return
+		}
+		// do a non-blocking send the same as for subscribers of single events
+		select {
+		case c <- batch:
+		default:
+			logrus.WithField("events", len(batch)).Warn("containerd: event batch not sent to subscriber")
+		}
+		batch = nil
+	}
+	for {
+		select {
+		case e, ok := <-events:
+			if !ok {
+				send()
+				return
+			}
+			batch = append(batch, e)
+			if len(batch) >= size {
+				send()
+				continue
+			}
+			if timer == nil {
+				timer = time.NewTimer(latency)
+				flush = timer.C
+			}
+		case <-flush:
+			timer, flush = nil, nil
+			send()
+		}
+	}
+}
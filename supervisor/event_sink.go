@@ -0,0 +1,84 @@
+package supervisor
+
+import (
+	"encoding/json"
+	"syscall"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+)
+
+// EventSink receives every event from a subscription of its own
+type EventSink interface {
+	// Send delivers the event, errors are logged and the event is dropped
+	Send(e Event) error
+	// Close is called once the subscription of the sink is closed
+	Close() error
+}
+
+// WithEventSink delivers events to the sink
+func WithEventSink(sink EventSink) Option {
+	return func(s *Supervisor) {
+		s.sinks = append(s.sinks, sink)
+	}
+}
+
+func runSink(sink EventSink, events chan Event) {
+	for e := range events {
+		if err := sink.Send(e); err != nil {
+			logrus.WithFields(logrus.Fields{
+				"error": err,
+				"event": e.Type,
+			}).Debug("containerd: event not sent to sink")
+		}
+	}
+	if err := sink.Close(); err != nil {
+		logrus.WithField("error", err).Error("containerd: close event sink")
+	}
+}
+
+// unixgramSink writes each event as a json datagram to a unix datagram socket
+type unixgramSink struct {
+	fd   int
+	addr *syscall.SockaddrUnix
+}
+
+// NewUnixgramSink returns an event sink that writes each event as a json datagram to
+// the unix datagram socket at path.  Datagrams are sent without blocking so events
+// are dropped when the receiver is slow or not listening.  The socket is addressed
+// by path for every event so a receiver that recreates the socket keeps receiving.
+func NewUnixgramSink(path string) (EventSink, error) {
+	fd, err := syscall.Socket(syscall.AF_UNIX, syscall.SOCK_DGRAM|syscall.SOCK_CLOEXEC, 0)
+	if err != nil {
+		return nil, err
+	}
+	return &unixgramSink{
+		fd:   fd,
+		addr: &syscall.SockaddrUnix{Name: path},
+	}, nil
+}
+
+func (u *unixgramSink) Send(e Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	for {
+		err := syscall.Sendto(u.fd, data, syscall.MSG_DONTWAIT, u.addr)
+		if err != syscall.EINTR {
+			return err
+		}
+	}
+}
+
+func (u *unixgramSink) Close() error {
+	return syscall.Close(u.fd)
+}
+
+// startSinks subscribes the event sinks
+func (s *Supervisor) startSinks() {
+	for _, sink := range s.sinks {
+		events, _ := s.subscribe(time.Time{}, false)
+		go runSink(sink, events)
+	}
+}
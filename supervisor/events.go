@@ -0,0 +1,200 @@
+package supervisor
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+)
+
+// CorrelationIDKey is the event metadata key holding the correlation id of the
+// task that generated the event
+const CorrelationIDKey = "correlationId"
+
+// WithMaxEventSize sets the maximum size in bytes of a json encoded event.  Events
+// larger than n have their metadata replaced with a truncation marker before they
+// are sent to subscribers and the journal.  Zero means unlimited.
+func WithMaxEventSize(n int) Option {
+	return func(s *Supervisor) {
+		s.maxEventSize = n
+	}
+}
+
+// truncateEvent returns e with its metadata replaced by a "truncated" marker, holding
+// the original encoded size, if the encoded event is larger than the configured maximum.
+func (s *Supervisor) truncateEvent(e Event) Event {
+	if s.maxEventSize <= 0 || len(e.Metadata) == 0 {
+		return e
+	}
+	data, err := json.Marshal(e)
+	if err != nil || len(data) <= s.maxEventSize {
+		return e
+	}
+	logrus.WithFields(logrus.Fields{
+		"id":   e.ID,
+		"type": e.Type,
+		"size": len(data),
+	}).Warn("containerd: event exceeds maximum size")
+	e.Metadata = map[string]string{
+		"truncated": strconv.Itoa(len(data)),
+	}
+	return e
+}
+
+// withCorrelationID returns e with the correlation id added to its metadata
+func withCorrelationID(e Event, id string) Event {
+	if id == "" {
+		return e
+	}
+	m := make(map[string]string, len(e.Metadata)+1)
+	for k, v := range e.Metadata {
+		m[k] = v
+	}
+	m[CorrelationIDKey] = id
+	e.Metadata = m
+	return e
+}
+
+// appendEvent adds the event to the in memory event log and its index
+func (s *Supervisor) appendEvent(e Event) {
+	s.eventLock.Lock()
+	s.eventsByType[e.Type] = append(s.eventsByType[e.Type], len(s.eventLog))
+	s.eventLog = append(s.eventLog, e)
+	s.eventLock.Unlock()
+}
+
+// trimEvents removes the oldest n events from the event log and its index
+func (s *Supervisor) trimEvents(n int) {
+	s.eventLock.Lock()
+	defer s.eventLock.Unlock()
+	if n > len(s.eventLog) {
+		n = len(s.eventLog)
+	}
+	// copy the remaining events so that the removed events can be collected
+	s.eventLog = append([]Event(nil), s.eventLog[n:]...)
+	s.eventsByType = make(map[string][]int)
+	for i, e := range s.eventLog {
+		s.eventsByType[e.Type] = append(s.eventsByType[e.Type], i)
+	}
+}
+
+// eventsOfType returns the events in the event log of type t with a timestamp within
+// from and to.  A zero from or to leaves that side of the range open.
+func (s *Supervisor) eventsOfType(t string, from, to time.Time) []Event {
+	s.eventLock.RLock()
+	defer s.eventLock.RUnlock()
+	var out []Event
+	for _, i := range s.eventsByType[t] {
+		e := s.eventLog[i]
+		if !from.IsZero() && e.Timestamp.Before(from) {
+			continue
+		}
+		if !to.IsZero() && e.Timestamp.After(to) {
+			continue
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
+type EventsByTypeTask struct {
+	s *Supervisor
+}
+
+// Handle returns the events of e.EventType across all containers, optionally
+// limited to the time range e.From to e.To
+func (h *EventsByTypeTask) Handle(e *Task) error {
+	e.Events = h.s.eventsOfType(e.EventType, e.From, e.To)
+	return nil
+}
+
+// EventLogStats are statistics about the event log
+type EventLogStats struct {
+	// Total is the number of events in the event log
+	Total int
+	// Types is the number of events per event type
+	Types map[string]int
+	// Containers are the containers with the most events, most events first
+	Containers []ContainerEventCount
+	// First and Last are the timestamps of the oldest and newest events
+	First time.Time
+	Last  time.Time
+	// Size is the size of the journal on disk in bytes
+	Size int64
+}
+
+// ContainerEventCount is the number of events for a container
+type ContainerEventCount struct {
+	ID    string
+	Count int
+}
+
+type containerEventCounts []ContainerEventCount
+
+func (c containerEventCounts) Len() int {
+	return len(c)
+}
+
+func (c containerEventCounts) Swap(i, j int) {
+	c[i], c[j] = c[j], c[i]
+}
+
+func (c containerEventCounts) Less(i, j int) bool {
+	if c[i].Count == c[j].Count {
+		return c[i].ID < c[j].ID
+	}
+	return c[i].Count > c[j].Count
+}
+
+type EventLogStatsTask struct {
+	s *Supervisor
+}
+
+// Handle computes the statistics from the in memory event log, e.Limit is the
+// number of containers returned with the most events, zero returns all containers.
+func (h *EventLogStatsTask) Handle(e *Task) error {
+	st := &EventLogStats{
+		Types: make(map[string]int),
+	}
+	containers := make(map[string]int)
+	h.s.eventLock.RLock()
+	st.Total = len(h.s.eventLog)
+	for t, i := range h.s.eventsByType {
+		st.Types[t] = len(i)
+	}
+	for _, ev := range h.s.eventLog {
+		if ev.ID != "" {
+			containers[ev.ID]++
+		}
+	}
+	if st.Total > 0 {
+		st.First = h.s.eventLog[0].Timestamp
+		st.Last = h.s.eventLog[st.Total-1].Timestamp
+	}
+	h.s.eventLock.RUnlock()
+	for id, n := range containers {
+		st.Containers = append(st.Containers, ContainerEventCount{ID: id, Count: n})
+	}
+	sort.Sort(containerEventCounts(st.Containers))
+	if e.Limit > 0 && len(st.Containers) > e.Limit {
+		st.Containers = st.Containers[:e.Limit]
+	}
+	fi, err := os.Stat(h.s.journalPath())
+	if err != nil {
+		return err
+	}
+	st.Size = fi.Size()
+	e.EventLogStats = st
+	return nil
+}
+
+// WithMaxSubscribers limits the number of channels returned by Events that can be
+// subscribed at the same time.  Zero means unlimited.
+func WithMaxSubscribers(n int) Option {
+	return func(s *Supervisor) {
+		s.maxSubscribers = n
+	}
+}
@@ -0,0 +1,78 @@
+package supervisor
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestEventsByTypeFromJournal(t *testing.T) {
+	dir, err := ioutil.TempDir("", "containerd-events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	s, err := New(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Start()
+	s.notifySubscribers(Event{
+		ID:        "test",
+		Type:      "test-event",
+		Timestamp: time.Now(),
+	})
+	if n := len(s.eventsOfType("test-event", time.Time{}, time.Time{})); n != 1 {
+		t.Fatalf("expected 1 event of type test-event but received %d", n)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	// a new supervisor indexes the events read from the journal
+	s, err = New(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	events := s.eventsOfType("test-event", time.Time{}, time.Time{})
+	if len(events) != 1 || events[0].ID != "test" {
+		t.Fatalf("expected the journaled event but received %v", events)
+	}
+}
+
+func TestEventsFromSeqReplaysDeliveredEvents(t *testing.T) {
+	dir, err := ioutil.TempDir("", "containerd-events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	s, err := New(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Start()
+	defer s.Close()
+	for i := 0; i < 3; i++ {
+		s.notifySubscribers(Event{
+			ID:        "test",
+			Type:      "test-event",
+			Timestamp: time.Now(),
+		})
+	}
+	// the events are replayed without waiting for the journal to write them
+	events, err := s.EventsFromSeq(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Unsubscribe(events)
+	for _, seq := range []uint64{2, 3} {
+		select {
+		case e := <-events:
+			if e.Seq != seq {
+				t.Fatalf("expected event %d but received %d", seq, e.Seq)
+			}
+		default:
+			t.Fatalf("expected event %d to be replayed", seq)
+		}
+	}
+}
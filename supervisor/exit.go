@@ -14,11 +14,26 @@ type ExitTask struct {
 func (h *ExitTask) Handle(e *Task) error {
 	start := time.Now()
 	proc := e.Process
+	// the exit for a process can be reported more than once, i.e. by restore and
+	// the monitor during startup, so only handle the first one
+	i, ok := h.s.containers[proc.Container().ID()]
+	if !ok {
+		containerLog(proc.Container().ID()).WithField("pid", proc.ID()).Debug("containerd: exit for removed container")
+		return nil
+	}
+	if _, ok := i.reaped[proc]; ok {
+		containerLog(proc.Container().ID()).WithField("pid", proc.ID()).Debug("containerd: duplicate process exit")
+		return nil
+	}
+	if i.reaped == nil {
+		i.reaped = make(map[runtime.Process]struct{})
+	}
+	i.reaped[proc] = struct{}{}
 	status, err := proc.ExitStatus()
 	if err != nil {
 		logrus.WithField("error", err).Error("containerd: get exit status")
 	}
-	logrus.WithFields(logrus.Fields{"pid": proc.ID(), "status": status}).Debug("containerd: process exited")
+	containerLog(proc.Container().ID()).WithFields(logrus.Fields{"pid": proc.ID(), "status": status}).Debug("containerd: process exited")
 
 	// if the process is the the init process of the container then
 	// fire a separate event for this process
@@ -28,7 +43,8 @@ func (h *ExitTask) Handle(e *Task) error {
 		ne.Pid = proc.ID()
 		ne.Status = status
 		ne.Process = proc
-		h.s.SendTask(ne)
+		ne.CorrelationID = e.CorrelationID
+		h.s.sendLifecycleTask(ne)
 
 		return nil
 	}
@@ -37,7 +53,9 @@ func (h *ExitTask) Handle(e *Task) error {
 	ne.ID = container.ID()
 	ne.Status = status
 	ne.Pid = proc.ID()
-	h.s.SendTask(ne)
+	ne.Process = proc
+	ne.CorrelationID = e.CorrelationID
+	h.s.sendLifecycleTask(ne)
 
 	ExitProcessTimer.UpdateSince(start)
 
@@ -50,16 +68,62 @@ type ExecExitTask struct {
 
 func (h *ExecExitTask) Handle(e *Task) error {
 	container := e.Process.Container()
-	// exec process: we remove this process without notifying the main event loop
-	if err := container.RemoveProcess(e.Pid); err != nil {
-		logrus.WithField("error", err).Error("containerd: find container for pid")
+	removeProcess := func() {
+		// exec process: we remove this process without notifying the main event loop
+		if err := container.RemoveProcess(e.Pid); err != nil {
+			logrus.WithField("error", err).Error("containerd: find container for pid")
+		}
+		h.s.updateSnapshot(container, "")
+	}
+	// the exit was delivered before a restart but the process was not removed
+	if e.Process.ExitDelivered() {
+		containerLog(e.ID).WithField("pid", e.Pid).Debug("containerd: exit already delivered")
+		removeProcess()
+		return nil
 	}
-	h.s.notifySubscribers(Event{
+	evt := Event{
 		Timestamp: time.Now(),
 		ID:        e.ID,
 		Type:      "exit",
 		Pid:       e.Pid,
 		Status:    e.Status,
-	})
+	}
+	h.s.capturesLock.Lock()
+	capture, ok := h.s.captures[e.Process]
+	delete(h.s.captures, e.Process)
+	h.s.capturesLock.Unlock()
+	if !ok {
+		h.s.notifySubscribers(withCorrelationID(evt, e.CorrelationID))
+		markExitDelivered(e.Process)
+		removeProcess()
+		return nil
+	}
+	// the process is removed before its exit is delivered so the exit is lost
+	// rather than repeated if the daemon restarts before the output is read
+	removeProcess()
+	// the remaining output is read outside of the event loop
+	go func() {
+		capture.wait(captureWaitTimeout)
+		capture.close()
+		evt.Metadata = map[string]string{
+			"stdout": capture.stdout.String(),
+			"stderr": capture.stderr.String(),
+		}
+		if capture.stdout.Truncated() || capture.stderr.Truncated() {
+			evt.Metadata["outputTruncated"] = "true"
+		}
+		h.s.notifySubscribers(withCorrelationID(evt, e.CorrelationID))
+	}()
 	return nil
 }
+
+// markExitDelivered records that the process's exit event was sent so that it is not
+// sent again for the process when it is restored
+func markExitDelivered(p runtime.Process) {
+	if err := p.MarkExitDelivered(); err != nil {
+		containerLog(p.Container().ID()).WithFields(logrus.Fields{
+			"pid":   p.ID(),
+			"error": err,
+		}).Warn("containerd: mark exit delivered")
+	}
+}
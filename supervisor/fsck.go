@@ -0,0 +1,211 @@
+package supervisor
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/containerd/runtime"
+)
+
+// FsckProblem is an inconsistency found in a state directory
+type FsckProblem struct {
+	Path    string
+	Problem string
+	// Repaired is true if the problem was safe to repair and was repaired
+	Repaired bool
+}
+
+type FsckTask struct {
+	s *Supervisor
+}
+
+// fsckState is a copy of the supervisor's state taken on the event loop that the
+// state directories are checked against outside of it
+type fsckState struct {
+	stateDir    string
+	stateDirs   map[string]string
+	containers  map[string]bool
+	quarantined map[string]error
+	missing     map[string]bool
+}
+
+// fsckRepair is a problem found by a fsck task with the repair that is applied on the
+// event loop
+type fsckRepair struct {
+	problem FsckProblem
+	repair  func() error
+}
+
+// Handle checks the state directories for inconsistencies and returns them in
+// e.FsckProblems.  If e.Repair is set the problems that are safe to repair, such as
+// orphaned temporary files, are repaired.  Containers are never modified.  The state
+// directories are walked outside of the event loop and the problems are sent back to
+// it to be repaired.
+func (h *FsckTask) Handle(e *Task) error {
+	state := fsckState{
+		stateDir:    h.s.stateDir,
+		stateDirs:   make(map[string]string, len(h.s.stateDirs)),
+		containers:  make(map[string]bool, len(h.s.containers)),
+		quarantined: make(map[string]error, len(h.s.quarantined)),
+		missing:     make(map[string]bool),
+	}
+	for id, root := range h.s.stateDirs {
+		state.stateDirs[id] = root
+	}
+	for id := range h.s.containers {
+		state.containers[id] = true
+	}
+	for id, err := range h.s.quarantined {
+		state.quarantined[id] = err
+	}
+	h.s.missingBundleLock.Lock()
+	for id := range h.s.missingBundles {
+		state.missing[id] = true
+	}
+	h.s.missingBundleLock.Unlock()
+	go func() {
+		repairs, err := h.check(state)
+		if err != nil {
+			e.Err <- err
+			return
+		}
+		if err := h.s.el.Send(&fsckResultEvent{
+			s:       h.s,
+			task:    e,
+			repairs: repairs,
+		}); err != nil {
+			e.Err <- err
+		}
+	}()
+	return errDeferedResponse
+}
+
+// check walks the state directories and returns the problems found
+func (h *FsckTask) check(state fsckState) ([]fsckRepair, error) {
+	var repairs []fsckRepair
+	report := func(path, problem string, repair func() error) {
+		repairs = append(repairs, fsckRepair{
+			problem: FsckProblem{
+				Path:    path,
+				Problem: problem,
+			},
+			repair: repair,
+		})
+	}
+	roots := map[string]bool{
+		state.stateDir: true,
+	}
+	for id, root := range state.stateDirs {
+		if _, err := os.Stat(filepath.Join(root, id)); err != nil {
+			id, root := id, root
+			report(filepath.Join(root, id), "alternate state directory is missing", func() error {
+				// the container may have been deleted or started again since
+				if r, ok := h.s.stateDirs[id]; !ok || r != root {
+					return nil
+				}
+				delete(h.s.stateDirs, id)
+				return h.s.saveStateDirs()
+			})
+			continue
+		}
+		roots[root] = true
+	}
+	for root := range roots {
+		if err := checkFsckRoot(state, root, report); err != nil {
+			return nil, err
+		}
+	}
+	for id := range state.containers {
+		root := state.stateDir
+		if r, ok := state.stateDirs[id]; ok {
+			root = r
+		}
+		if _, err := os.Stat(filepath.Join(root, id)); err != nil {
+			report(filepath.Join(root, id), "state directory of container is missing", nil)
+		}
+	}
+	return repairs, nil
+}
+
+// fsckResultEvent returns the problems found by a fsck task, after repairing them if
+// requested, on the event loop
+type fsckResultEvent struct {
+	s       *Supervisor
+	task    *Task
+	repairs []fsckRepair
+}
+
+func (r *fsckResultEvent) Handle() {
+	problems := make([]FsckProblem, 0, len(r.repairs))
+	for _, fr := range r.repairs {
+		p := fr.problem
+		if r.task.Repair && fr.repair != nil {
+			if err := fr.repair(); err != nil {
+				p.Problem += ": repair failed: " + err.Error()
+			} else {
+				p.Repaired = true
+			}
+		}
+		problems = append(problems, p)
+	}
+	r.task.FsckProblems = problems
+	r.task.Err <- nil
+}
+
+func checkFsckRoot(state fsckState, root string, report func(string, string, func() error)) error {
+	entries, err := ioutil.ReadDir(root)
+	if err != nil {
+		return err
+	}
+	for _, fi := range entries {
+		path := filepath.Join(root, fi.Name())
+		if !fi.IsDir() {
+			// temporary files are written and renamed from the event loop so any
+			// that still exist when the repair runs on it were left behind by a
+			// crash
+			if strings.HasSuffix(fi.Name(), ".tmp") {
+				report(path, "orphaned temporary file", func() error {
+					if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+						return err
+					}
+					return nil
+				})
+			}
+			continue
+		}
+		if (root == state.stateDir && fi.Name() == spoolDir) || fi.Name() == corruptDir {
+			continue
+		}
+		checkFsckContainer(state, root, fi.Name(), report)
+	}
+	return nil
+}
+
+func checkFsckContainer(state fsckState, root, id string, report func(string, string, func() error)) {
+	dir := filepath.Join(root, id)
+	if err, ok := state.quarantined[id]; ok {
+		report(dir, "container is quarantined: "+err.Error(), nil)
+	} else if state.missing[id] {
+		report(dir, "bundle of container is missing", nil)
+	} else if !state.containers[id] {
+		report(dir, "directory does not belong to a container", nil)
+	}
+	if _, err := os.Stat(filepath.Join(dir, runtime.StateFile)); err != nil {
+		report(dir, "container state file is missing", nil)
+	}
+	processes, err := ioutil.ReadDir(dir)
+	if err != nil {
+		report(dir, "unable to read container directory: "+err.Error(), nil)
+		return
+	}
+	for _, p := range processes {
+		if !p.IsDir() || p.Name() == runtime.InitBundleDir {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(dir, p.Name(), "process.json")); err != nil {
+			report(filepath.Join(dir, p.Name()), "process state file is missing", nil)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package supervisor
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFsckRepairsOrphanedTemporaryFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "containerd-fsck")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	s, err := New(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Start()
+	defer s.Close()
+	path := filepath.Join(dir, stateDirsFile+".tmp")
+	if err := ioutil.WriteFile(path, []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	e := NewTask(FsckTaskType)
+	e.Repair = true
+	s.SendTask(e)
+	if err := <-e.Err; err != nil {
+		t.Fatal(err)
+	}
+	if len(e.FsckProblems) != 1 || e.FsckProblems[0].Path != path || !e.FsckProblems[0].Repaired {
+		t.Fatalf("expected the temporary file to be repaired but received %+v", e.FsckProblems)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("expected the temporary file to be removed but received %v", err)
+	}
+}
@@ -0,0 +1,39 @@
+package supervisor
+
+type GetHostnameTask struct {
+	s *Supervisor
+}
+
+// Handle returns the hostname of the UTS namespace of the container e.ID in
+// e.Hostname
+func (h *GetHostnameTask) Handle(e *Task) error {
+	i, ok := h.s.containers[e.ID]
+	if !ok {
+		return ErrContainerNotFound
+	}
+	name, err := i.container.Hostname()
+	if err != nil {
+		return err
+	}
+	e.Hostname = name
+	return nil
+}
+
+type SetHostnameTask struct {
+	s *Supervisor
+}
+
+// Handle sets the hostname of the UTS namespace of the container e.ID to
+// e.Hostname.  Processes in the container see the new hostname immediately, it
+// is not persisted to the container's bundle.
+func (h *SetHostnameTask) Handle(e *Task) error {
+	i, ok := h.s.containers[e.ID]
+	if !ok {
+		return ErrContainerNotFound
+	}
+	if err := i.container.SetHostname(e.Hostname); err != nil {
+		return err
+	}
+	containerLog(e.ID).WithField("hostname", e.Hostname).Debug("containerd: hostname set")
+	return nil
+}
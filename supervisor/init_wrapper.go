@@ -0,0 +1,22 @@
+package supervisor
+
+// WithInitWrapper sets the path of the init binary, i.e. tini, that is injected as
+// the init process of containers started with Task.Init.  The binary must be static
+// and run the process following its "--" argument, reaping zombies and forwarding
+// signals to it.
+func WithInitWrapper(path string) Option {
+	return func(s *Supervisor) {
+		s.initWrapper = path
+	}
+}
+
+// containerInitWrapper returns the init wrapper for a start task
+func (s *Supervisor) containerInitWrapper(e *Task) (string, error) {
+	if !e.Init {
+		return "", nil
+	}
+	if s.initWrapper == "" {
+		return "", ErrNoInitWrapper
+	}
+	return s.initWrapper, nil
+}
@@ -0,0 +1,111 @@
+package supervisor
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/docker/containerd/runtime"
+	"github.com/opencontainers/specs"
+)
+
+// ContainerInspect is a consolidated view of a container
+type ContainerInspect struct {
+	ID          string
+	BundlePath  string
+	State       runtime.State
+	Labels      []string
+	Annotations map[string]string
+	Spec        *specs.LinuxSpec
+	Resources   *specs.Resources
+	Processes   []runtime.Process
+	Checkpoints []runtime.Checkpoint
+	// Stats are the most recent stats collected in the background
+	Stats *runtime.Stat
+	// LastExit is the last exit event of one of the container's processes
+	LastExit *Event
+	// RestartCount is the number of times a container with the id was started
+	// after its first start, as recorded in the event log
+	RestartCount int
+	// Errors holds the reason for each part of the view that could not be gathered
+	Errors map[string]string
+}
+
+type InspectTask struct {
+	s *Supervisor
+}
+
+// Handle gathers everything known about a container.  A failure to gather one
+// part of the view is recorded in its Errors instead of failing the task.
+func (h *InspectTask) Handle(e *Task) error {
+	i, ok := h.s.containers[e.ID]
+	if !ok {
+		return ErrContainerNotFound
+	}
+	c := i.container
+	info := &ContainerInspect{
+		ID:         c.ID(),
+		BundlePath: c.Path(),
+		State:      c.State(),
+		Labels:     c.Labels(),
+		Errors:     make(map[string]string),
+	}
+	if spec, err := c.Spec(); err != nil {
+		info.Errors["spec"] = err.Error()
+	} else {
+		info.Spec = spec
+		info.Resources = spec.Linux.Resources
+	}
+	if annotations, err := bundleAnnotations(c.Path()); err != nil {
+		info.Errors["annotations"] = err.Error()
+	} else {
+		info.Annotations = annotations
+	}
+	if processes, err := c.Processes(); err != nil {
+		info.Errors["processes"] = err.Error()
+	} else {
+		info.Processes = processes
+	}
+	if checkpoints, err := c.Checkpoints(""); err != nil {
+		info.Errors["checkpoints"] = err.Error()
+	} else {
+		info.Checkpoints = checkpoints
+	}
+	if stats := h.s.collector.history(c.ID()); len(stats) > 0 {
+		info.Stats = stats[len(stats)-1]
+	}
+	for _, ev := range h.s.eventsOfType("exit", time.Time{}, time.Time{}) {
+		if ev.ID == c.ID() {
+			ev := ev
+			info.LastExit = &ev
+		}
+	}
+	for _, ev := range h.s.eventsOfType("start-container", time.Time{}, time.Time{}) {
+		if ev.ID == c.ID() {
+			info.RestartCount++
+		}
+	}
+	if info.RestartCount > 0 {
+		info.RestartCount--
+	}
+	e.Inspect = info
+	return nil
+}
+
+// bundleAnnotations returns the annotations in the config of the bundle, they are
+// not part of the spec that the runtime reads
+func bundleAnnotations(bundle string) (map[string]string, error) {
+	f, err := os.Open(filepath.Join(bundle, "config.json"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var config struct {
+		Annotations map[string]string `json:"annotations"`
+	}
+	if err := json.NewDecoder(f).Decode(&config); err != nil {
+		return nil, err
+	}
+	return config.Annotations, nil
+}
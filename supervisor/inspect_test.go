@@ -0,0 +1,99 @@
+package supervisor
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/docker/containerd/runtime"
+	"github.com/opencontainers/specs"
+)
+
+// testContainer is a container that only has an id
+type testContainer struct {
+	runtime.Container
+	id string
+}
+
+func (c *testContainer) ID() string {
+	return c.id
+}
+
+// inspectContainer is a container whose spec cannot be read
+type inspectContainer struct {
+	testContainer
+	bundle string
+}
+
+func (c *inspectContainer) Path() string {
+	return c.bundle
+}
+
+func (c *inspectContainer) State() runtime.State {
+	return runtime.Running
+}
+
+func (c *inspectContainer) Labels() []string {
+	return nil
+}
+
+func (c *inspectContainer) Spec() (*specs.LinuxSpec, error) {
+	return nil, os.ErrNotExist
+}
+
+func (c *inspectContainer) Processes() ([]runtime.Process, error) {
+	return nil, nil
+}
+
+func (c *inspectContainer) Checkpoints(path string) ([]runtime.Checkpoint, error) {
+	return nil, nil
+}
+
+func TestInspectAnnotationsAndRestartCount(t *testing.T) {
+	dir, err := ioutil.TempDir("", "containerd-inspect")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	bundle := filepath.Join(dir, "bundle")
+	if err := os.Mkdir(bundle, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(bundle, "config.json"), []byte(`{"annotations":{"owner":"test"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := New(filepath.Join(dir, "state"), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Start()
+	for i := 0; i < 2; i++ {
+		s.notifySubscribers(Event{
+			ID:        "test",
+			Type:      "start-container",
+			Timestamp: time.Now(),
+		})
+	}
+	for i := 0; i < 100 && len(s.eventsOfType("start-container", time.Time{}, time.Time{})) < 2; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	s.containers["test"] = &containerInfo{
+		container: &inspectContainer{testContainer: testContainer{id: "test"}, bundle: bundle},
+	}
+	e := NewTask(InspectTaskType)
+	e.ID = "test"
+	if err := (&InspectTask{s}).Handle(e); err != nil {
+		t.Fatal(err)
+	}
+	if owner := e.Inspect.Annotations["owner"]; owner != "test" {
+		t.Fatalf("expected the annotation owner=test but received %v", e.Inspect.Annotations)
+	}
+	if e.Inspect.RestartCount != 1 {
+		t.Fatalf("expected a restart count of 1 but received %d", e.Inspect.RestartCount)
+	}
+	if _, ok := e.Inspect.Errors["spec"]; !ok {
+		t.Fatal("expected the spec error to be reported")
+	}
+}
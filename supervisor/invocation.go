@@ -0,0 +1,37 @@
+package supervisor
+
+import "github.com/docker/containerd/runtime"
+
+type InvocationTask struct {
+	s *Supervisor
+}
+
+// Handle returns the runtime command that was executed to start the process with the
+// id e.Pid, or the container's init process if e.Pid is empty.  Unlike the process's
+// spec it includes the runtime args and the init wrapper of the container.
+func (h *InvocationTask) Handle(e *Task) error {
+	i, ok := h.s.containers[e.ID]
+	if !ok {
+		return ErrContainerNotFound
+	}
+	id := e.Pid
+	if id == "" {
+		id = runtime.InitProcessID
+	}
+	processes, err := i.container.Processes()
+	if err != nil {
+		return err
+	}
+	for _, p := range processes {
+		if p.ID() != id {
+			continue
+		}
+		inv, err := p.Invocation()
+		if err != nil {
+			return err
+		}
+		e.Invocation = inv
+		return nil
+	}
+	return ErrProcessNotFound
+}
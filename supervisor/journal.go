@@ -0,0 +1,292 @@
+package supervisor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+)
+
+const (
+	// eventLogFile is the name of the event journal
+	eventLogFile = "events.log"
+	// defaultJournalBufferLimit is the size of the events that are kept for the
+	// journal while they cannot be written
+	defaultJournalBufferLimit = 4 * 1024 * 1024
+)
+
+// criticalEvents are flushed to the journal as soon as they are written
+var criticalEvents = map[string]bool{
+	"exit": true,
+}
+
+// WithJournalFlush buffers writes to the event journal and flushes them after n
+// events or when interval has passed, whichever comes first.  Critical events such
+// as exits are always flushed immediately.  By default every event is flushed.
+func WithJournalFlush(n int, interval time.Duration) Option {
+	return func(s *Supervisor) {
+		s.journalFlushCount = n
+		s.journalFlushInterval = interval
+	}
+}
+
+// WithJournalBufferLimit sets the size in bytes of the events that are kept for the
+// journal while they cannot be written, i.e. while the disk is full.  The oldest
+// events are dropped from the journal once the limit is reached, they are still
+// replayed to subscribers until the supervisor restarts.  A limit of 0 or less
+// keeps every event.
+func WithJournalBufferLimit(n int) Option {
+	return func(s *Supervisor) {
+		s.journalBufferLimit = n
+	}
+}
+
+// WithJournalDir stores the event journal in dir instead of the state directory
+func WithJournalDir(dir string) Option {
+	return func(s *Supervisor) {
+		s.journalDir = dir
+	}
+}
+
+// WithJournalRotation rotates the event journal to "events.log.1" once it is maxSize
+// bytes or holds maxEvents events, shifting older segments to "events.log.2" and so
+// on.  Up to segments rotated segments are kept, older segments are removed from disk
+// and their events from the in memory event log that is replayed to subscribers.
+// Zero values for maxSize and maxEvents mean unlimited.
+func WithJournalRotation(maxSize int64, maxEvents, segments int) Option {
+	return func(s *Supervisor) {
+		s.journalMaxSize = maxSize
+		s.journalMaxEvents = maxEvents
+		s.journalSegments = segments
+	}
+}
+
+// segmentPath returns the path of the nth rotated segment of the journal at path
+func segmentPath(path string, n int) string {
+	return fmt.Sprintf("%s.%d", path, n)
+}
+
+// journalPath returns the path of the event journal
+func (s *Supervisor) journalPath() string {
+	if s.journalDir != "" {
+		return filepath.Join(s.journalDir, eventLogFile)
+	}
+	return filepath.Join(s.stateDir, eventLogFile)
+}
+
+// journal writes json encoded events to the events.log file
+type journal struct {
+	f *os.File
+	// buf holds the encoded events that have not been written to f.  It is used
+	// instead of a bufio.Writer so that the unwritten events can be retried after
+	// a failed write, i.e. when the disk is full.
+	buf           bytes.Buffer
+	enc           *json.Encoder
+	flushCount    int
+	flushInterval time.Duration
+	pending       int
+	// bufferLimit is the size that buf is trimmed to when a write fails, partial is
+	// true if the first event in buf was partly written and must be kept
+	bufferLimit int
+	partial     bool
+	// path is the path of the journal file, the rotation settings and state are
+	// unused if maxSize and maxEvents are zero
+	path      string
+	maxSize   int64
+	maxEvents int
+	keep      int
+	// size and events are the size and number of events of the current segment
+	size   int64
+	events int
+	// segments holds the number of events in each of the rotated segments, oldest first
+	segments []int
+}
+
+func newJournal(f *os.File, flushCount int, flushInterval time.Duration) *journal {
+	j := &journal{
+		f:             f,
+		flushCount:    flushCount,
+		flushInterval: flushInterval,
+	}
+	j.enc = json.NewEncoder(&j.buf)
+	return j
+}
+
+func (j *journal) write(e Event) error {
+	n := j.buf.Len()
+	if err := j.enc.Encode(e); err != nil {
+		return err
+	}
+	j.size += int64(j.buf.Len() - n)
+	j.events++
+	j.pending++
+	if j.pending >= j.flushCount || criticalEvents[e.Type] {
+		return j.flush()
+	}
+	return nil
+}
+
+func (j *journal) flush() error {
+	if j.buf.Len() == 0 {
+		return nil
+	}
+	data := j.buf.Bytes()
+	n, err := j.f.Write(data)
+	if n > 0 {
+		j.partial = data[n-1] != '\n'
+	}
+	j.buf.Next(n)
+	if err != nil {
+		j.trim()
+		return err
+	}
+	j.pending = 0
+	return nil
+}
+
+// trim drops the oldest unwritten events until buf is within bufferLimit and
+// returns the number of events dropped
+func (j *journal) trim() int {
+	if j.bufferLimit <= 0 || j.buf.Len() <= j.bufferLimit {
+		return 0
+	}
+	var (
+		data    = j.buf.Bytes()
+		start   int
+		dropped int
+	)
+	if j.partial {
+		start = bytes.IndexByte(data, '\n') + 1
+	}
+	end := start
+	for len(data)-(end-start) > j.bufferLimit {
+		i := bytes.IndexByte(data[end:], '\n')
+		if i < 0 {
+			break
+		}
+		end += i + 1
+		dropped++
+	}
+	if dropped == 0 {
+		return 0
+	}
+	rest := append(append([]byte{}, data[:start]...), data[end:]...)
+	j.buf.Reset()
+	j.buf.Write(rest)
+	JournalDroppedEventsCounter.Inc(int64(dropped))
+	logrus.WithField("count", dropped).Warn("containerd: dropped unwritten events from the journal")
+	return dropped
+}
+
+// full returns true if the current segment must be rotated
+func (j *journal) full() bool {
+	return (j.maxSize > 0 && j.size >= j.maxSize) || (j.maxEvents > 0 && j.events >= j.maxEvents)
+}
+
+// rotate flushes the current segment and starts a new one, the oldest segment is
+// removed along with its events in the event log if there are more than keep
+func (j *journal) rotate(s *Supervisor) error {
+	if err := j.flush(); err != nil {
+		return err
+	}
+	if err := j.f.Close(); err != nil {
+		return err
+	}
+	if err := os.Remove(segmentPath(j.path, j.keep+1)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for i := j.keep; i >= 1; i-- {
+		if err := os.Rename(segmentPath(j.path, i), segmentPath(j.path, i+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if err := os.Rename(j.path, segmentPath(j.path, 1)); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(j.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0755)
+	if err != nil {
+		return err
+	}
+	j.f = f
+	j.segments = append(j.segments, j.events)
+	j.size, j.events = 0, 0
+	if len(j.segments) > j.keep {
+		pruned := j.segments[0]
+		j.segments = j.segments[1:]
+		if err := os.Remove(segmentPath(j.path, j.keep+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		s.trimEvents(pruned)
+	}
+	return nil
+}
+
+// close flushes the buffered events, syncs and closes the journal file
+func (j *journal) close() error {
+	err := j.flush()
+	if serr := j.f.Sync(); err == nil {
+		err = serr
+	}
+	if cerr := j.f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// run writes the events to the journal until the events channel is closed and
+// returns the result of closing the journal
+func (j *journal) run(s *Supervisor, events chan Event) error {
+	var (
+		tick  <-chan time.Time
+		retry <-chan time.Time
+	)
+	if j.flushInterval > 0 {
+		t := time.NewTicker(j.flushInterval)
+		defer t.Stop()
+		tick = t.C
+	}
+	// handle enters or leaves the disk full mode of the supervisor based on the
+	// result of a write and schedules a retry for the unwritten events
+	handle := func(err error) {
+		if err == nil {
+			if j.buf.Len() == 0 {
+				s.setDiskFull(false, "")
+			}
+			return
+		}
+		logrus.WithField("error", err).Error("containerd: write event to journal")
+		if isNoSpace(err) {
+			s.setDiskFull(true, eventLogFile)
+			if retry == nil {
+				retry = time.After(s.diskFullRetryInterval)
+			}
+		}
+	}
+	for {
+		select {
+		case e, ok := <-events:
+			if !ok {
+				err := j.close()
+				if err != nil {
+					logrus.WithField("error", err).Error("containerd: close event journal")
+				}
+				return err
+			}
+			handle(j.write(e))
+			if j.full() {
+				if err := j.rotate(s); err != nil {
+					logrus.WithField("error", err).Error("containerd: rotate event journal")
+				}
+			}
+		case <-tick:
+			handle(j.flush())
+		case <-retry:
+			retry = nil
+			handle(j.flush())
+		}
+	}
+}
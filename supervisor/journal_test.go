@@ -0,0 +1,59 @@
+package supervisor
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func TestJournalDropsOldestEventsWhenWritesFail(t *testing.T) {
+	dir, err := ioutil.TempDir("", "containerd-journal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	// writes to a read only file fail like writes to a full disk
+	f, err := os.OpenFile(filepath.Join(dir, eventLogFile), os.O_RDONLY|os.O_CREATE, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	j := newJournal(f, 1, 0)
+	j.bufferLimit = 256
+	before := JournalDroppedEventsCounter.Count()
+	for i := 0; i < 20; i++ {
+		if err := j.write(Event{ID: strconv.Itoa(i), Type: "test"}); err == nil {
+			t.Fatal("expected the write to fail")
+		}
+	}
+	if j.buf.Len() > j.bufferLimit {
+		t.Fatalf("expected at most %d buffered bytes but received %d", j.bufferLimit, j.buf.Len())
+	}
+	if JournalDroppedEventsCounter.Count() == before {
+		t.Fatal("expected the dropped events to be counted")
+	}
+	// the newest events are kept in order
+	lines := bytes.Split(bytes.TrimSpace(j.buf.Bytes()), []byte("\n"))
+	var last Event
+	if err := json.Unmarshal(lines[len(lines)-1], &last); err != nil {
+		t.Fatal(err)
+	}
+	if last.ID != "19" {
+		t.Fatalf("expected the newest event to be kept but received %q", last.ID)
+	}
+	f.Close()
+}
+
+func TestJournalTrimKeepsPartlyWrittenEvent(t *testing.T) {
+	j := &journal{bufferLimit: 12, partial: true}
+	j.buf.WriteString("tail\nfirst event\nsecond\n")
+	if n := j.trim(); n != 1 {
+		t.Fatalf("expected 1 event to be dropped but received %d", n)
+	}
+	if s := j.buf.String(); s != "tail\nsecond\n" {
+		t.Fatalf("expected the partly written event to be kept but received %q", s)
+	}
+}
@@ -0,0 +1,53 @@
+package supervisor
+
+import (
+	"strconv"
+	"time"
+)
+
+// WithLagWarning sends a lag-warning event to a subscriber when the events
+// waiting in its channel reach highWater, a fraction of the channel's buffer,
+// so that it can catch up or reconnect before events are dropped.  The warning
+// is sent again once the subscriber has drained below half of highWater.  Zero
+// disables lag warnings.
+func WithLagWarning(highWater float64) Option {
+	return func(s *Supervisor) {
+		if highWater > 1 {
+			highWater = 1
+		}
+		s.lagHighWater = highWater
+	}
+}
+
+// warnLag is called by deliver after an event is sent to the subscriber, the
+// lagging subscribers are protected by seqLock and subscriberLock
+func (s *Supervisor) warnLag(sub chan Event, seq uint64) {
+	if s.lagHighWater <= 0 || cap(sub) == 0 {
+		return
+	}
+	used := float64(len(sub)) / float64(cap(sub))
+	if s.laggingSubscribers[sub] {
+		if used < s.lagHighWater/2 {
+			delete(s.laggingSubscribers, sub)
+		}
+		return
+	}
+	if used < s.lagHighWater {
+		return
+	}
+	// the warning is not part of the event log so it has no sequence number of
+	// its own
+	select {
+	case sub <- Event{
+		Type:      "lag-warning",
+		Timestamp: time.Now(),
+		Metadata: map[string]string{
+			"buffered": strconv.Itoa(len(sub)),
+			"capacity": strconv.Itoa(cap(sub)),
+			"lastSeq":  strconv.FormatUint(seq, 10),
+		},
+	}:
+		s.laggingSubscribers[sub] = true
+	default:
+	}
+}
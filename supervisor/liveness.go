@@ -0,0 +1,108 @@
+package supervisor
+
+import (
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/docker/containerd/runtime"
+	"github.com/opencontainers/runc/libcontainer/system"
+)
+
+// WithLivenessInterval checks every interval that the init process of each
+// container is still alive and emits a liveness-lost event for the containers
+// whose init process is gone without its exit being received by the monitor.
+// A zero interval disables the checks.
+func WithLivenessInterval(interval time.Duration) Option {
+	return func(s *Supervisor) {
+		s.livenessInterval = interval
+	}
+}
+
+// livenessRecord is the init process of a container seen by the liveness probe
+type livenessRecord struct {
+	pid       int
+	startTime string
+	// lost is set once the process is reported so that it is reported only once
+	lost bool
+}
+
+// probeLiveness compares the init process of every container with the process
+// seen on the previous probe until the supervisor is stopped.  A pid that no
+// longer exists or was reused by a process with a different start time is
+// reported to the event loop which only emits an event if the exit has still not
+// been handled.
+func (s *Supervisor) probeLiveness() {
+	seen := make(map[string]*livenessRecord)
+	t := time.NewTicker(s.livenessInterval)
+	defer t.Stop()
+	for range t.C {
+		if atomic.LoadInt32(&s.stopping) == 1 {
+			return
+		}
+		current := make(map[string]*livenessRecord)
+		for _, c := range s.Containers() {
+			if c.InitPid == 0 || c.State == runtime.Stopped {
+				continue
+			}
+			r, ok := seen[c.ID]
+			if !ok || r.pid != c.InitPid {
+				startTime, err := system.GetProcessStartTime(c.InitPid)
+				if err != nil {
+					// the exit of a process that is gone before it is first
+					// probed is left to the monitor
+					continue
+				}
+				r = &livenessRecord{pid: c.InitPid, startTime: startTime}
+			}
+			current[c.ID] = r
+			if r.lost {
+				continue
+			}
+			if startTime, err := system.GetProcessStartTime(r.pid); err != nil || startTime != r.startTime {
+				r.lost = true
+				e := NewTask(LivenessLostTaskType)
+				e.ID = c.ID
+				e.SystemPid = r.pid
+				s.SendTask(e)
+			}
+		}
+		seen = current
+	}
+}
+
+type LivenessLostTask struct {
+	s *Supervisor
+}
+
+// Handle emits a liveness-lost event if the lost init process is still the init
+// process of the container, i.e. its exit was not handled before the task, and no
+// exit status was recorded for it, in which case the monitor reports the exit.
+func (h *LivenessLostTask) Handle(e *Task) error {
+	i, ok := h.s.containers[e.ID]
+	if !ok {
+		return ErrContainerNotFound
+	}
+	processes, err := i.container.Processes()
+	if err != nil {
+		return err
+	}
+	for _, p := range processes {
+		if p.ID() != runtime.InitProcessID || p.SystemPid() != e.SystemPid {
+			continue
+		}
+		if _, err := p.ExitStatus(); err == nil {
+			return nil
+		}
+		containerLog(e.ID).WithField(systemPidKey, e.SystemPid).Warn("containerd: init process is gone without an exit")
+		h.s.notifySubscribers(withCorrelationID(Event{
+			ID:        e.ID,
+			Type:      "liveness-lost",
+			Timestamp: time.Now(),
+			Metadata: map[string]string{
+				systemPidKey: strconv.Itoa(e.SystemPid),
+			},
+		}, e.CorrelationID))
+	}
+	return nil
+}
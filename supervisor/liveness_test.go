@@ -0,0 +1,81 @@
+package supervisor
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/docker/containerd/runtime"
+)
+
+// livenessProcess is an init process whose exit status is exitErr
+type livenessProcess struct {
+	testProcess
+	pid     int
+	exitErr error
+}
+
+func (p *livenessProcess) SystemPid() int {
+	return p.pid
+}
+
+func (p *livenessProcess) ExitStatus() (int, error) {
+	return 0, p.exitErr
+}
+
+// livenessContainer is a container with a single process
+type livenessContainer struct {
+	testContainer
+	process runtime.Process
+}
+
+func (c *livenessContainer) Processes() ([]runtime.Process, error) {
+	return []runtime.Process{c.process}, nil
+}
+
+func TestLivenessLostSkipsExitedProcess(t *testing.T) {
+	dir, err := ioutil.TempDir("", "containerd-liveness")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	s, err := New(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Start()
+	defer s.Close()
+	for _, test := range []struct {
+		id      string
+		exitErr error
+		events  int
+	}{
+		{"exited", nil, 0},
+		{"gone", runtime.ErrProcessNotExited, 1},
+	} {
+		s.containers[test.id] = &containerInfo{container: &livenessContainer{
+			testContainer: testContainer{id: test.id},
+			process: &livenessProcess{
+				testProcess: testProcess{id: runtime.InitProcessID},
+				pid:         100,
+				exitErr:     test.exitErr,
+			},
+		}}
+		e := NewTask(LivenessLostTaskType)
+		e.ID = test.id
+		e.SystemPid = 100
+		if err := (&LivenessLostTask{s}).Handle(e); err != nil {
+			t.Fatal(err)
+		}
+		var n int
+		for _, ev := range s.eventsOfType("liveness-lost", time.Time{}, time.Time{}) {
+			if ev.ID == test.id {
+				n++
+			}
+		}
+		if n != test.events {
+			t.Fatalf("%s: expected %d liveness-lost events but received %d", test.id, test.events, n)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package supervisor
+
+import (
+	"sync"
+
+	"github.com/Sirupsen/logrus"
+)
+
+var (
+	containerLoggersLock sync.RWMutex
+	containerLoggers     = make(map[string]*logrus.Logger)
+)
+
+// SetContainerLogLevel sets the level of the log messages about the container with the
+// provided id independently of the global log level.  This allows debug logging for a
+// single container on a busy host.
+func SetContainerLogLevel(id string, level logrus.Level) {
+	std := logrus.StandardLogger()
+	containerLoggersLock.Lock()
+	containerLoggers[id] = &logrus.Logger{
+		Out:       std.Out,
+		Formatter: std.Formatter,
+		Hooks:     std.Hooks,
+		Level:     level,
+	}
+	containerLoggersLock.Unlock()
+}
+
+// ResetContainerLogLevel makes the log messages about the container use the global log level
+func ResetContainerLogLevel(id string) {
+	containerLoggersLock.Lock()
+	delete(containerLoggers, id)
+	containerLoggersLock.Unlock()
+}
+
+// containerLog returns a log entry for messages about the container with the provided id
+func containerLog(id string) *logrus.Entry {
+	containerLoggersLock.RLock()
+	l, ok := containerLoggers[id]
+	containerLoggersLock.RUnlock()
+	if !ok {
+		return logrus.WithField("id", id)
+	}
+	return l.WithField("id", id)
+}
@@ -0,0 +1,22 @@
+package supervisor
+
+import "github.com/Sirupsen/logrus"
+
+// LogEvents logs every event emitted by the supervisor at debug level so that the
+// application log lines up with the event stream.  It can be disabled to reduce
+// the log volume when running with debug logging.
+var LogEvents = true
+
+// logEvent logs the event, numbered by deliver, with structured fields
+func logEvent(e Event) {
+	if !LogEvents || logrus.GetLevel() < logrus.DebugLevel {
+		return
+	}
+	logrus.WithFields(logrus.Fields{
+		"id":     e.ID,
+		"type":   e.Type,
+		"pid":    e.Pid,
+		"status": e.Status,
+		"seq":    e.Seq,
+	}).Debug("containerd: event")
+}
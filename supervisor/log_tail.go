@@ -0,0 +1,129 @@
+package supervisor
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/docker/containerd/runtime"
+)
+
+const tailChunkSize = 4096
+
+// LogLines are the last lines written to a process's stdout and stderr
+type LogLines struct {
+	Stdout []string
+	Stderr []string
+}
+
+type LogTailTask struct {
+	s *Supervisor
+}
+
+// Handle returns the last e.Limit lines of the stdout and stderr of the process
+// with the id e.Pid, or the container's init process if e.Pid is empty.  The process's
+// stdio must be regular files, when a file has been rotated to "<path>.1" lines are
+// also read from the rotated file.  Fewer lines are returned if fewer exist.
+func (h *LogTailTask) Handle(e *Task) error {
+	i, ok := h.s.containers[e.ID]
+	if !ok {
+		return ErrContainerNotFound
+	}
+	id := e.Pid
+	if id == "" {
+		id = runtime.InitProcessID
+	}
+	processes, err := i.container.Processes()
+	if err != nil {
+		return err
+	}
+	for _, p := range processes {
+		if p.ID() != id {
+			continue
+		}
+		stdio := p.Stdio()
+		go func() {
+			var (
+				l   = &LogLines{}
+				err error
+			)
+			if l.Stdout, err = tailLog(stdio.Stdout, e.Limit); err == nil {
+				l.Stderr, err = tailLog(stdio.Stderr, e.Limit)
+			}
+			if err != nil {
+				e.Err <- err
+				return
+			}
+			e.LogLines = l
+			e.Err <- nil
+		}()
+		return errDeferedResponse
+	}
+	return ErrProcessNotFound
+}
+
+// tailLog returns the last n lines of the log file at path and its rotated file
+func tailLog(path string, n int) ([]string, error) {
+	if path == "/dev/null" {
+		return nil, nil
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if !fi.Mode().IsRegular() {
+		return nil, ErrNotLogFile
+	}
+	lines, err := tailLines(path, n)
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) < n {
+		rotated, err := tailLines(path+".1", n-len(lines))
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		lines = append(rotated, lines...)
+	}
+	return lines, nil
+}
+
+// tailLines reads the file at path backwards until it has found the last n lines
+func tailLines(path string, n int) ([]string, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	offset, err := f.Seek(0, os.SEEK_END)
+	if err != nil {
+		return nil, err
+	}
+	var data []byte
+	// a trailing newline terminates the last line, it does not start a new one
+	for offset > 0 && bytes.Count(bytes.TrimSuffix(data, []byte("\n")), []byte("\n")) < n {
+		size := int64(tailChunkSize)
+		if offset < size {
+			size = offset
+		}
+		offset -= size
+		chunk := make([]byte, size)
+		if _, err := f.ReadAt(chunk, offset); err != nil && err != io.EOF {
+			return nil, err
+		}
+		data = append(chunk, data...)
+	}
+	data = bytes.TrimSuffix(data, []byte("\n"))
+	if len(data) == 0 {
+		return nil, nil
+	}
+	lines := strings.Split(string(data), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines, nil
+}
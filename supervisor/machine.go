@@ -1,9 +1,20 @@
 package supervisor
 
-import "github.com/cloudfoundry/gosigar"
+import (
+	"strconv"
+	"time"
 
+	"github.com/Sirupsen/logrus"
+	"github.com/cloudfoundry/gosigar"
+)
+
+// Machine is the information about the host that the supervisor runs on.  Both
+// fields can change at runtime when cpus or memory are hotplugged, they are only
+// updated by RefreshMachine.
 type Machine struct {
-	Cpus   int
+	// Cpus is the number of online cpus
+	Cpus int
+	// Memory is the total memory in MB
 	Memory int64
 }
 
@@ -21,3 +32,77 @@ func CollectMachineInformation() (Machine, error) {
 	m.Memory = int64(mem.Total / 1024 / 1024)
 	return m, nil
 }
+
+// EventsOption configures a subscription returned by Events
+type EventsOption func(*eventsConfig)
+
+type eventsConfig struct {
+	machine bool
+	// buffer is the size of the channel and overflow is set for reliable subscribers
+	buffer   int
+	overflow OverflowPolicy
+	// types are the event types received, nil receives all events
+	types map[string]bool
+	// container is the id of the container whose events are received, empty
+	// receives the events of all containers
+	container string
+}
+
+// WithMachineInfo sends a machine-info event carrying the machine information
+// to the subscriber when it connects and whenever the machine information is
+// refreshed.
+func WithMachineInfo() EventsOption {
+	return func(c *eventsConfig) {
+		c.machine = true
+	}
+}
+
+// RefreshMachine collects the machine information again and sends it to the
+// subscribers that requested machine-info events.  A machine-update event with the
+// previous values in its metadata is emitted to all subscribers if the information
+// changed.
+func (s *Supervisor) RefreshMachine() error {
+	m, err := CollectMachineInformation()
+	if err != nil {
+		return err
+	}
+	s.machineLock.Lock()
+	old := s.machine
+	s.machine = m
+	s.machineLock.Unlock()
+	s.subscriberLock.RLock()
+	e := s.machineEvent()
+	for sub := range s.machineSubscribers {
+		select {
+		case sub <- e:
+		default:
+			logrus.WithField("event", e.Type).Warn("containerd: event not sent to subscriber")
+		}
+	}
+	s.subscriberLock.RUnlock()
+	if m != old {
+		logrus.WithFields(logrus.Fields{
+			"cpus":   m.Cpus,
+			"memory": m.Memory,
+		}).Info("containerd: machine information changed")
+		s.notifySubscribers(Event{
+			Type:      "machine-update",
+			Timestamp: time.Now(),
+			Machine:   &m,
+			Metadata: map[string]string{
+				"cpusOld":   strconv.Itoa(old.Cpus),
+				"memoryOld": strconv.FormatInt(old.Memory, 10),
+			},
+		})
+	}
+	return nil
+}
+
+func (s *Supervisor) machineEvent() Event {
+	m := s.Machine()
+	return Event{
+		Type:      "machine-info",
+		Timestamp: time.Now(),
+		Machine:   &m,
+	}
+}
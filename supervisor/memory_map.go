@@ -0,0 +1,115 @@
+package supervisor
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/docker/containerd/runtime"
+)
+
+// MemoryMap is a summary of a process's memory mappings read from
+// /proc/<pid>/smaps_rollup.  All values are in bytes.
+type MemoryMap struct {
+	Pid int
+	// Rss is the resident memory of the process
+	Rss uint64
+	// Pss is the resident memory with shared pages divided between the processes
+	// mapping them
+	Pss uint64
+	// Anonymous is the resident memory that is not backed by a file
+	Anonymous uint64
+	// FileBacked is the resident memory that is backed by a file, i.e. page cache
+	FileBacked   uint64
+	SharedClean  uint64
+	SharedDirty  uint64
+	PrivateClean uint64
+	PrivateDirty uint64
+	Swap         uint64
+}
+
+type MemoryMapTask struct {
+	s *Supervisor
+}
+
+// Handle reads the memory map of the container process with the id e.Pid, or the
+// container's init process if e.Pid is empty.
+func (h *MemoryMapTask) Handle(e *Task) error {
+	i, ok := h.s.containers[e.ID]
+	if !ok {
+		return ErrContainerNotFound
+	}
+	id := e.Pid
+	if id == "" {
+		id = runtime.InitProcessID
+	}
+	processes, err := i.container.Processes()
+	if err != nil {
+		return err
+	}
+	for _, p := range processes {
+		if p.ID() != id {
+			continue
+		}
+		pid := p.SystemPid()
+		// smaps_rollup walks all mappings of the process so don't block the event loop
+		go func() {
+			m, err := readMemoryMap(pid)
+			if err != nil {
+				e.Err <- err
+				return
+			}
+			e.Err <- nil
+			e.MemoryMap <- m
+		}()
+		return errDeferedResponse
+	}
+	return ErrProcessNotFound
+}
+
+func readMemoryMap(pid int) (*MemoryMap, error) {
+	f, err := os.Open(fmt.Sprintf("/proc/%d/smaps_rollup", pid))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	m := &MemoryMap{
+		Pid: pid,
+	}
+	fields := map[string]*uint64{
+		"Rss:":           &m.Rss,
+		"Pss:":           &m.Pss,
+		"Anonymous:":     &m.Anonymous,
+		"Shared_Clean:":  &m.SharedClean,
+		"Shared_Dirty:":  &m.SharedDirty,
+		"Private_Clean:": &m.PrivateClean,
+		"Private_Dirty:": &m.PrivateDirty,
+		"Swap:":          &m.Swap,
+	}
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		// lines are in the form "Rss:    1408 kB"
+		parts := strings.Fields(s.Text())
+		if len(parts) != 3 {
+			continue
+		}
+		v, ok := fields[parts[0]]
+		if !ok {
+			continue
+		}
+		kb, err := strconv.ParseUint(parts[1], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		*v = kb * 1024
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	if m.Rss > m.Anonymous {
+		m.FileBacked = m.Rss - m.Anonymous
+	}
+	return m, nil
+}
@@ -0,0 +1,121 @@
+package supervisor
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/docker/containerd/runtime"
+)
+
+// WithMemoryPressure emits a memory-pressure event when the memory cgroup of a
+// container reaches one of the levels, low, medium or critical, so that clients
+// can act before the container is OOM killed.
+func WithMemoryPressure(levels ...string) Option {
+	return func(s *Supervisor) {
+		s.pressureLevels = levels
+	}
+}
+
+// WithMemoryPressureDebounce collapses the memory pressure notifications received for
+// a container and level within d into a single memory-pressure event carrying the
+// number of notifications.
+func WithMemoryPressureDebounce(d time.Duration) Option {
+	return func(s *Supervisor) {
+		s.pressureDebounce = d
+	}
+}
+
+// memoryPressureKey identifies a watched pressure level in the pressure notifier
+type memoryPressureKey struct {
+	id    string
+	level string
+}
+
+// watchMemoryPressure registers the container's memory cgroup for the watched
+// pressure levels, the registrations are removed by the notifier when the
+// cgroup is removed
+func (s *Supervisor) watchMemoryPressure(c runtime.Container) {
+	if s.pressureNotifier == nil {
+		return
+	}
+	for _, l := range s.pressureLevels {
+		ch, err := c.MemoryPressure(l)
+		if err != nil {
+			containerLog(c.ID()).WithField("error", err).WithField("level", l).Error("containerd: notify memory pressure events")
+			continue
+		}
+		s.pressureNotifier.Add(memoryPressureKey{id: c.ID(), level: l}, ch)
+	}
+}
+
+// unwatchMemoryPressure removes the registrations of a deleted container from the
+// pressure notifier
+func (s *Supervisor) unwatchMemoryPressure(id string) {
+	if s.pressureNotifier == nil {
+		return
+	}
+	for _, l := range s.pressureLevels {
+		// the registration is already gone if the cgroup was removed first
+		s.pressureNotifier.Remove(memoryPressureKey{id: id, level: l})
+	}
+}
+
+// memoryPressureHandler sends a memory pressure task to the event loop for the
+// notifications received from the pressure notifier, debouncing them per container
+// and level when configured
+func (s *Supervisor) memoryPressureHandler() {
+	var (
+		m       sync.Mutex
+		pending = make(map[memoryPressureKey]int)
+	)
+	for v := range s.pressureNotifier.Chan() {
+		k := v.(memoryPressureKey)
+		if s.pressureDebounce <= 0 {
+			s.sendMemoryPressureTask(k, 1)
+			continue
+		}
+		m.Lock()
+		n, ok := pending[k]
+		pending[k] = n + 1
+		m.Unlock()
+		if ok {
+			continue
+		}
+		time.AfterFunc(s.pressureDebounce, func() {
+			m.Lock()
+			count := pending[k]
+			delete(pending, k)
+			m.Unlock()
+			s.sendMemoryPressureTask(k, count)
+		})
+	}
+}
+
+func (s *Supervisor) sendMemoryPressureTask(k memoryPressureKey, count int) {
+	e := NewTask(MemoryPressureTaskType)
+	e.ID = k.id
+	e.PressureLevel = k.level
+	e.Count = count
+	s.SendTask(e)
+}
+
+type MemoryPressureTask struct {
+	s *Supervisor
+}
+
+func (h *MemoryPressureTask) Handle(e *Task) error {
+	if _, ok := h.s.containers[e.ID]; !ok {
+		return ErrContainerNotFound
+	}
+	h.s.notifySubscribers(withCorrelationID(Event{
+		ID:        e.ID,
+		Type:      "memory-pressure",
+		Timestamp: time.Now(),
+		Metadata: map[string]string{
+			"level": e.PressureLevel,
+			"count": strconv.Itoa(e.Count),
+		},
+	}, e.CorrelationID))
+	return nil
+}
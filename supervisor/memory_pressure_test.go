@@ -0,0 +1,45 @@
+package supervisor
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestMemoryPressureDebounce(t *testing.T) {
+	dir, err := ioutil.TempDir("", "containerd-memory-pressure")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	s, err := New(dir, false, WithMemoryPressure("low"), WithMemoryPressureDebounce(100*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Start()
+	defer s.Close()
+	s.containers["test"] = &containerInfo{container: &testContainer{id: "test"}}
+	k := memoryPressureKey{id: "test", level: "low"}
+	ch := make(chan struct{})
+	if err := s.pressureNotifier.Add(k, ch); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		ch <- struct{}{}
+	}
+	var events []Event
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		if events = s.eventsOfType("memory-pressure", time.Time{}, time.Time{}); len(events) > 0 {
+			break
+		}
+	}
+	if len(events) != 1 || events[0].Metadata["count"] != "3" {
+		t.Fatalf("expected 1 memory-pressure event with a count of 3 but received %v", events)
+	}
+	// the registration is released on delete so the channel can be added again
+	s.unwatchMemoryPressure("test")
+	if err := s.pressureNotifier.Add(k, ch); err != nil {
+		t.Fatalf("expected the registration to be removed but received %v", err)
+	}
+}
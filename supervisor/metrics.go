@@ -3,29 +3,43 @@ package supervisor
 import "github.com/rcrowley/go-metrics"
 
 var (
-	ContainerCreateTimer   = metrics.NewTimer()
-	ContainerDeleteTimer   = metrics.NewTimer()
-	ContainerStartTimer    = metrics.NewTimer()
-	ContainerStatsTimer    = metrics.NewTimer()
-	ContainersCounter      = metrics.NewCounter()
-	EventSubscriberCounter = metrics.NewCounter()
-	TasksCounter           = metrics.NewCounter()
-	ExecProcessTimer       = metrics.NewTimer()
-	ExitProcessTimer       = metrics.NewTimer()
-	EpollFdCounter         = metrics.NewCounter()
+	ContainerCreateTimer    = metrics.NewTimer()
+	ContainerDeleteTimer    = metrics.NewTimer()
+	ContainerStartTimer     = metrics.NewTimer()
+	ContainerStatsTimer     = metrics.NewTimer()
+	ContainerDiskUsageTimer = metrics.NewTimer()
+	ContainersCounter       = metrics.NewCounter()
+	EventSubscriberCounter  = metrics.NewCounter()
+	TasksCounter            = metrics.NewCounter()
+	ExecProcessTimer        = metrics.NewTimer()
+	ExitProcessTimer        = metrics.NewTimer()
+	EpollFdCounter          = metrics.NewCounter()
+	UnknownTasksCounter     = metrics.NewCounter()
+	// JournalDroppedEventsCounter is the number of events that were dropped from
+	// the journal because they could not be written
+	JournalDroppedEventsCounter = metrics.NewCounter()
+	// EventLoopDepthGauge is the number of tasks waiting in the event loop and
+	// EventLoopLatencyTimer is the time tasks wait before they are handled
+	EventLoopDepthGauge   = metrics.NewGauge()
+	EventLoopLatencyTimer = metrics.NewTimer()
 )
 
 func Metrics() map[string]interface{} {
 	return map[string]interface{}{
-		"container-create-time": ContainerCreateTimer,
-		"container-delete-time": ContainerDeleteTimer,
-		"container-start-time":  ContainerStartTimer,
-		"container-stats-time":  ContainerStatsTimer,
-		"containers":            ContainersCounter,
-		"event-subscribers":     EventSubscriberCounter,
-		"tasks":                 TasksCounter,
-		"exec-process-time":     ExecProcessTimer,
-		"exit-process-time":     ExitProcessTimer,
-		"epoll-fds":             EpollFdCounter,
+		"container-create-time":     ContainerCreateTimer,
+		"container-delete-time":     ContainerDeleteTimer,
+		"container-start-time":      ContainerStartTimer,
+		"container-stats-time":      ContainerStatsTimer,
+		"container-disk-usage-time": ContainerDiskUsageTimer,
+		"containers":                ContainersCounter,
+		"event-subscribers":         EventSubscriberCounter,
+		"tasks":                     TasksCounter,
+		"exec-process-time":         ExecProcessTimer,
+		"exit-process-time":         ExitProcessTimer,
+		"epoll-fds":                 EpollFdCounter,
+		"unknown-tasks":             UnknownTasksCounter,
+		"journal-dropped-events":    JournalDroppedEventsCounter,
+		"event-loop-depth":          EventLoopDepthGauge,
+		"event-loop-latency":        EventLoopLatencyTimer,
 	}
 }
@@ -0,0 +1,153 @@
+package supervisor
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/docker/containerd/runtime"
+)
+
+// MissingBundlePolicy decides how a container whose bundle does not exist is handled
+// on restore
+type MissingBundlePolicy string
+
+const (
+	// MissingBundleRestore restores the container regardless of its bundle
+	MissingBundleRestore MissingBundlePolicy = ""
+	// MissingBundleQuarantine quarantines the container the same as a container
+	// that failed to load
+	MissingBundleQuarantine MissingBundlePolicy = "quarantine"
+	// MissingBundleRecord does not restore the container but keeps a record of it
+	// that is returned by MissingBundles until it is removed
+	MissingBundleRecord MissingBundlePolicy = "record"
+)
+
+// MissingBundle is a container that was not restored because its bundle is missing
+type MissingBundle struct {
+	ID     string
+	Bundle string
+	// StateDir is the directory holding the container's state directory
+	StateDir string
+}
+
+// WithMissingBundlePolicy sets how containers whose bundle is missing on restore,
+// i.e. because it is on storage that is not mounted yet, are handled
+func WithMissingBundlePolicy(p MissingBundlePolicy) Option {
+	return func(s *Supervisor) {
+		s.missingBundlePolicy = p
+	}
+}
+
+// bundleMissing applies the missing bundle policy to a container being restored and
+// returns true if the container must not be restored.  A container with processes
+// that are still running is always restored so that their exits are monitored.
+func (s *Supervisor) bundleMissing(root, id, bundle string, processes []runtime.Process) bool {
+	if s.missingBundlePolicy == MissingBundleRestore {
+		return false
+	}
+	if _, err := os.Stat(bundle); !os.IsNotExist(err) {
+		return false
+	}
+	for _, p := range processes {
+		if _, err := p.ExitStatus(); err != nil {
+			containerLog(id).WithField("bundle", bundle).Warn("containerd: bundle of running container is missing")
+			return false
+		}
+	}
+	containerLog(id).WithField("bundle", bundle).Warn("containerd: bundle of container is missing")
+	switch s.missingBundlePolicy {
+	case MissingBundleQuarantine:
+		s.quarantined[id] = ErrBundleMissing
+	default:
+		s.missingBundleLock.Lock()
+		s.missingBundles[id] = MissingBundle{
+			ID:       id,
+			Bundle:   bundle,
+			StateDir: root,
+		}
+		s.missingBundleLock.Unlock()
+	}
+	return true
+}
+
+// MissingBundles returns the containers that were not restored because their bundle
+// was missing, sorted by id
+func (s *Supervisor) MissingBundles() []MissingBundle {
+	s.missingBundleLock.Lock()
+	defer s.missingBundleLock.Unlock()
+	out := make([]MissingBundle, 0, len(s.missingBundles))
+	for _, m := range s.missingBundles {
+		out = append(out, m)
+	}
+	sort.Sort(missingBundles(out))
+	return out
+}
+
+// RemoveMissingBundle removes the state of a container that was not restored because
+// its bundle was missing
+func (s *Supervisor) RemoveMissingBundle(id string) error {
+	e := &removeMissingBundleEvent{
+		s:   s,
+		id:  id,
+		err: make(chan error, 1),
+	}
+	if err := s.el.Send(e); err != nil {
+		return err
+	}
+	return <-e.err
+}
+
+// removeMissingBundleEvent removes a container with a missing bundle on the event
+// loop which owns the state directories and stop configurations of containers
+type removeMissingBundleEvent struct {
+	s   *Supervisor
+	id  string
+	err chan error
+}
+
+func (e *removeMissingBundleEvent) Handle() {
+	e.err <- e.s.removeMissingBundle(e.id)
+}
+
+func (s *Supervisor) removeMissingBundle(id string) error {
+	s.missingBundleLock.Lock()
+	m, ok := s.missingBundles[id]
+	if !ok {
+		s.missingBundleLock.Unlock()
+		return ErrContainerNotFound
+	}
+	if err := os.RemoveAll(filepath.Join(m.StateDir, id)); err != nil {
+		s.missingBundleLock.Unlock()
+		return err
+	}
+	delete(s.missingBundles, id)
+	s.missingBundleLock.Unlock()
+	if _, ok := s.stateDirs[id]; ok {
+		delete(s.stateDirs, id)
+		if err := s.saveStateDirs(); err != nil {
+			return err
+		}
+	}
+	if _, ok := s.stopConfigs[id]; ok {
+		delete(s.stopConfigs, id)
+		if err := s.saveStopConfigs(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type missingBundles []MissingBundle
+
+func (m missingBundles) Len() int {
+	return len(m)
+}
+
+func (m missingBundles) Swap(i, j int) {
+	m[i], m[j] = m[j], m[i]
+}
+
+func (m missingBundles) Less(i, j int) bool {
+	return m[i].ID < m[j].ID
+}
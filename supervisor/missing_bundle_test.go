@@ -0,0 +1,59 @@
+package supervisor
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRemoveMissingBundleForgetsContainer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "containerd-missing-bundle")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	stateDir := filepath.Join(dir, "state")
+	s, err := New(stateDir, false, WithMissingBundlePolicy(MissingBundleRecord))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Start()
+	defer s.Close()
+	root := filepath.Join(dir, "other")
+	if err := os.MkdirAll(filepath.Join(root, "test"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	s.missingBundles["test"] = MissingBundle{ID: "test", Bundle: filepath.Join(dir, "bundle"), StateDir: root}
+	s.stateDirs["test"] = root
+	s.stopConfigs["test"] = stopConfig{Policy: StopPolicyKill}
+	if err := s.saveStateDirs(); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.saveStopConfigs(); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.RemoveMissingBundle("test"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "test")); !os.IsNotExist(err) {
+		t.Fatalf("expected the state of the container to be removed but received %v", err)
+	}
+	if n := len(s.MissingBundles()); n != 0 {
+		t.Fatalf("expected no missing bundles but received %d", n)
+	}
+	dirs, err := loadStateDirs(stateDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := dirs["test"]; ok {
+		t.Fatal("expected the state directory of the container to be forgotten")
+	}
+	configs, err := loadStopConfigs(stateDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := configs["test"]; ok {
+		t.Fatal("expected the stop configuration of the container to be forgotten")
+	}
+}
@@ -1,23 +1,66 @@
 package supervisor
 
 import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
+	"time"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/docker/containerd/runtime"
 )
 
+const (
+	defaultExitErrorRetries  = 3
+	defaultExitRetryInterval = 100 * time.Millisecond
+	livenessPollInterval     = time.Second
+	// defaultExitLivenessTimeout is how long a process is polled through /proc
+	// before the monitor gives up on detecting its exit
+	defaultExitLivenessTimeout = 24 * time.Hour
+)
+
+// WithExitErrorRetries sets how many times the monitor re-registers a process whose
+// exit fd reported an error, waiting interval between attempts, before it falls back
+// to checking /proc for the process to detect its exit.
+func WithExitErrorRetries(retries int, interval time.Duration) Option {
+	return func(s *Supervisor) {
+		s.monitor.errorRetries = retries
+		s.monitor.retryInterval = interval
+	}
+}
+
+// WithExitLivenessTimeout sets how long the monitor polls /proc for the exit of a
+// process whose exit fd could not be recovered before giving up.
+func WithExitLivenessTimeout(timeout time.Duration) Option {
+	return func(s *Supervisor) {
+		s.monitor.livenessTimeout = timeout
+	}
+}
+
 func NewMonitor() (*Monitor, error) {
 	m := &Monitor{
-		processes: make(map[int]runtime.Process),
-		exits:     make(chan runtime.Process, 1024),
+		processes:       make(map[int]runtime.Process),
+		exits:           make(chan runtime.Process, 1024),
+		errors:          make(map[runtime.Process]int),
+		startTimes:      make(map[runtime.Process]string),
+		errorRetries:    defaultExitErrorRetries,
+		retryInterval:   defaultExitRetryInterval,
+		livenessTimeout: defaultExitLivenessTimeout,
 	}
 	fd, err := syscall.EpollCreate1(0)
 	if err != nil {
 		return nil, err
 	}
 	m.epollFd = fd
+	// set before the loop starts so that the monitor is healthy as soon as it is
+	// returned
+	m.running = 1
 	go m.start()
 	return m, nil
 }
@@ -27,6 +70,42 @@ type Monitor struct {
 	processes map[int]runtime.Process
 	exits     chan runtime.Process
 	epollFd   int
+	// errors counts the errors reported for the exit fd of a process
+	errors map[runtime.Process]int
+	// startTimes is the start time of each process, from /proc/<pid>/stat, when it
+	// was first monitored so that a reused pid is not mistaken for the process
+	startTimes      map[runtime.Process]string
+	errorRetries    int
+	retryInterval   time.Duration
+	livenessTimeout time.Duration
+	// running is 1 while the epoll loop is running and lastExit is the time, in
+	// unix nanoseconds, of the last exit detected; both are accessed atomically
+	running  int32
+	lastExit int64
+}
+
+// MonitorHealth is the state of the monitor's exit detection
+type MonitorHealth struct {
+	// Running is true while the epoll loop is running
+	Running bool
+	// Processes is the number of processes currently monitored
+	Processes int
+	// LastExit is the time of the last exit detected, zero if none
+	LastExit time.Time
+}
+
+// Health returns the state of the monitor's exit detection
+func (m *Monitor) Health() MonitorHealth {
+	m.m.Lock()
+	h := MonitorHealth{
+		Running:   atomic.LoadInt32(&m.running) == 1,
+		Processes: len(m.processes),
+	}
+	m.m.Unlock()
+	if t := atomic.LoadInt64(&m.lastExit); t != 0 {
+		h.LastExit = time.Unix(0, t)
+	}
+	return h
 }
 
 func (m *Monitor) Exits() chan runtime.Process {
@@ -36,6 +115,11 @@ func (m *Monitor) Exits() chan runtime.Process {
 func (m *Monitor) Monitor(p runtime.Process) error {
 	m.m.Lock()
 	defer m.m.Unlock()
+	if _, ok := m.startTimes[p]; !ok {
+		if _, startTime, err := processStat(p.SystemPid()); err == nil {
+			m.startTimes[p] = startTime
+		}
+	}
 	fd := p.ExitFD()
 	event := syscall.EpollEvent{
 		Fd:     int32(fd),
@@ -54,6 +138,7 @@ func (m *Monitor) Close() error {
 }
 
 func (m *Monitor) start() {
+	defer atomic.StoreInt32(&m.running, 0)
 	var events [128]syscall.EpollEvent
 	for {
 		n, err := syscall.EpollWait(m.epollFd, events[:], -1)
@@ -61,28 +146,130 @@ func (m *Monitor) start() {
 			if err == syscall.EINTR {
 				continue
 			}
+			if err == syscall.EBADF {
+				logrus.WithField("error", err).Error("containerd: epoll fd closed, exits are no longer detected")
+				return
+			}
 			logrus.WithField("error", err).Fatal("containerd: epoll wait")
 		}
 		// process events
 		for i := 0; i < n; i++ {
-			if events[i].Events == syscall.EPOLLHUP {
-				fd := int(events[i].Fd)
+			fd := int(events[i].Fd)
+			switch {
+			case events[i].Events == syscall.EPOLLHUP:
 				m.m.Lock()
-				proc := m.processes[fd]
-				delete(m.processes, fd)
-				if err = syscall.EpollCtl(m.epollFd, syscall.EPOLL_CTL_DEL, fd, &syscall.EpollEvent{
-					Events: syscall.EPOLLHUP,
-					Fd:     int32(fd),
-				}); err != nil {
-					logrus.WithField("error", err).Fatal("containerd: epoll remove fd")
-				}
-				EpollFdCounter.Dec(1)
+				proc := m.remove(fd)
+				delete(m.errors, proc)
+				delete(m.startTimes, proc)
 				if err := proc.Close(); err != nil {
 					logrus.WithField("error", err).Error("containerd: close process IO")
 				}
 				m.m.Unlock()
+				atomic.StoreInt64(&m.lastExit, time.Now().UnixNano())
 				m.exits <- proc
+			case events[i].Events&syscall.EPOLLERR != 0:
+				m.m.Lock()
+				proc := m.remove(fd)
+				m.errors[proc]++
+				attempt := m.errors[proc]
+				m.m.Unlock()
+				containerLog(proc.Container().ID()).WithFields(logrus.Fields{
+					"pid":     proc.ID(),
+					"attempt": attempt,
+				}).Warn("containerd: error on process exit fd")
+				go m.recover(proc, attempt)
 			}
 		}
 	}
 }
+
+// remove removes the fd from epoll and returns its process, the lock must be held
+func (m *Monitor) remove(fd int) runtime.Process {
+	proc := m.processes[fd]
+	delete(m.processes, fd)
+	if err := syscall.EpollCtl(m.epollFd, syscall.EPOLL_CTL_DEL, fd, &syscall.EpollEvent{
+		Events: syscall.EPOLLHUP,
+		Fd:     int32(fd),
+	}); err != nil {
+		logrus.WithField("error", err).Fatal("containerd: epoll remove fd")
+	}
+	EpollFdCounter.Dec(1)
+	return proc
+}
+
+// recover re-registers a process after an error on its exit fd.  Once the retries
+// are exhausted the process is polled through /proc and its exit is only reported
+// after the process is confirmed to be gone.  Polling stops without reporting an
+// exit if the process is still alive after the liveness timeout.
+func (m *Monitor) recover(proc runtime.Process, attempt int) {
+	if attempt <= m.errorRetries {
+		time.Sleep(m.retryInterval)
+		if err := m.Monitor(proc); err == nil {
+			return
+		}
+	}
+	m.m.Lock()
+	startTime := m.startTimes[proc]
+	m.m.Unlock()
+	var (
+		pid      = proc.SystemPid()
+		deadline = time.Now().Add(m.livenessTimeout)
+		exited   = true
+	)
+	for processAlive(pid, startTime) {
+		if time.Now().After(deadline) {
+			exited = false
+			break
+		}
+		time.Sleep(livenessPollInterval)
+	}
+	m.m.Lock()
+	delete(m.errors, proc)
+	delete(m.startTimes, proc)
+	m.m.Unlock()
+	if !exited {
+		containerLog(proc.Container().ID()).WithFields(logrus.Fields{
+			"pid":       proc.ID(),
+			"systemPid": pid,
+			"timeout":   m.livenessTimeout,
+		}).Error("containerd: process still alive, no longer detecting its exit")
+		return
+	}
+	if err := proc.Close(); err != nil {
+		logrus.WithField("error", err).Error("containerd: close process IO")
+	}
+	m.exits <- proc
+}
+
+// processAlive returns false if the pid does not exist, is a zombie or, when
+// startTime is not empty, was reused by a process with a different start time
+func processAlive(pid int, startTime string) bool {
+	state, st, err := processStat(pid)
+	if err != nil {
+		return false
+	}
+	if startTime != "" && st != startTime {
+		return false
+	}
+	return state != 'Z'
+}
+
+// processStat returns the state and the start time, field 22, of the pid from
+// /proc/<pid>/stat
+func processStat(pid int) (byte, string, error) {
+	data, err := ioutil.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "stat"))
+	if err != nil {
+		return 0, "", err
+	}
+	// the fields after the command name, which is enclosed in parentheses and may
+	// contain spaces, start with the state in field 3
+	i := bytes.LastIndexByte(data, ')')
+	if i < 0 {
+		return 0, "", fmt.Errorf("containerd: invalid stat for pid %d", pid)
+	}
+	fields := strings.Fields(string(data[i+1:]))
+	if len(fields) < 22-2 {
+		return 0, "", fmt.Errorf("containerd: invalid stat for pid %d", pid)
+	}
+	return fields[0][0], fields[22-3], nil
+}
@@ -0,0 +1,26 @@
+package supervisor
+
+import (
+	"os"
+	"testing"
+)
+
+func TestProcessAliveComparesStartTime(t *testing.T) {
+	pid := os.Getpid()
+	state, startTime, err := processStat(pid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state == 'Z' {
+		t.Fatalf("expected the test process not to be a zombie")
+	}
+	if !processAlive(pid, startTime) {
+		t.Fatal("expected the test process to be alive")
+	}
+	if !processAlive(pid, "") {
+		t.Fatal("expected the test process to be alive without a start time")
+	}
+	if processAlive(pid, startTime+"0") {
+		t.Fatal("expected a reused pid to not be alive")
+	}
+}
@@ -0,0 +1,86 @@
+package supervisor
+
+import (
+	"strconv"
+	"sync"
+	"time"
+)
+
+// WithOOMDebounce collapses the OOM notifications received for a container
+// within d into a single oom event carrying the number of notifications.
+func WithOOMDebounce(d time.Duration) Option {
+	return func(s *Supervisor) {
+		s.oomDebounce = d
+	}
+}
+
+// oomHandler sends an OOM task to the event loop for the notifications received
+// from the notifier, debouncing them per container when configured
+func (s *Supervisor) oomHandler() {
+	var (
+		m       sync.Mutex
+		pending = make(map[string]int)
+	)
+	for v := range s.notifier.Chan() {
+		id := v.(string)
+		if s.oomDebounce <= 0 {
+			s.sendOOMTask(id, 1)
+			continue
+		}
+		m.Lock()
+		n, ok := pending[id]
+		pending[id] = n + 1
+		m.Unlock()
+		if ok {
+			continue
+		}
+		time.AfterFunc(s.oomDebounce, func() {
+			m.Lock()
+			count := pending[id]
+			delete(pending, id)
+			m.Unlock()
+			s.sendOOMTask(id, count)
+		})
+	}
+}
+
+func (s *Supervisor) sendOOMTask(id string, count int) {
+	e := NewTask(OOMTaskType)
+	e.ID = id
+	e.Count = count
+	s.SendTask(e)
+}
+
+type OOMTask struct {
+	s *Supervisor
+}
+
+func (h *OOMTask) Handle(e *Task) error {
+	if _, ok := h.s.containers[e.ID]; !ok {
+		return ErrContainerNotFound
+	}
+	h.s.notifySubscribers(withCorrelationID(Event{
+		ID:        e.ID,
+		Type:      "oom",
+		Timestamp: time.Now(),
+		Metadata: map[string]string{
+			"count": strconv.Itoa(e.Count),
+		},
+	}, e.CorrelationID))
+	return nil
+}
+
+type OOMHistoryTask struct {
+	s *Supervisor
+}
+
+// Handle returns all the oom events in the event log for the container, the
+// container does not have to exist anymore.
+func (h *OOMHistoryTask) Handle(e *Task) error {
+	for _, ev := range h.s.eventsOfType("oom", time.Time{}, time.Time{}) {
+		if ev.ID == e.ID {
+			e.Events = append(e.Events, ev)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+package supervisor
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/containerd/runtime"
+)
+
+const (
+	otlpBatchSize     = 100
+	otlpFlushInterval = time.Second
+	otlpTimeout       = 5 * time.Second
+)
+
+// WithOTLPExporter exports events to an OpenTelemetry collector using OTLP over
+// http with json encoding, i.e. "http://localhost:4318".  Every event is exported
+// as a log record and the lifetime of each container, from its start to the exit of
+// its init process, as a span.  Events are exported from their own subscription so
+// export failures never affect the journal.
+func WithOTLPExporter(endpoint string) Option {
+	return func(s *Supervisor) {
+		s.otlpEndpoint = strings.TrimSuffix(endpoint, "/")
+	}
+}
+
+type otlpExporter struct {
+	endpoint string
+	client   *http.Client
+	logs     []otlpLogRecord
+	spans    []otlpSpan
+	// started holds the span of each running container
+	started map[string]otlpSpan
+}
+
+func newOTLPExporter(endpoint string) *otlpExporter {
+	return &otlpExporter{
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: otlpTimeout},
+		started:  make(map[string]otlpSpan),
+	}
+}
+
+func (x *otlpExporter) run(events chan Event) {
+	t := time.NewTicker(otlpFlushInterval)
+	defer t.Stop()
+	for {
+		select {
+		case e, ok := <-events:
+			if !ok {
+				x.flush()
+				return
+			}
+			x.add(e)
+			if len(x.logs) >= otlpBatchSize {
+				x.flush()
+			}
+		case <-t.C:
+			x.flush()
+		}
+	}
+}
+
+func (x *otlpExporter) add(e Event) {
+	attrs := []otlpAttribute{
+		stringAttribute("container.id", e.ID),
+		stringAttribute("event.type", e.Type),
+	}
+	if e.Pid != "" {
+		attrs = append(attrs, stringAttribute("process.id", e.Pid))
+	}
+	if e.Type == "exit" {
+		attrs = append(attrs, stringAttribute("process.exit_status", strconv.Itoa(e.Status)))
+	}
+	for k, v := range e.Metadata {
+		attrs = append(attrs, stringAttribute("containerd."+k, v))
+	}
+	x.logs = append(x.logs, otlpLogRecord{
+		TimeUnixNano: unixNano(e.Timestamp),
+		SeverityText: "INFO",
+		Body:         otlpValue{StringValue: e.Type},
+		Attributes:   attrs,
+	})
+	switch {
+	case e.Type == "start-container":
+		x.started[e.ID] = otlpSpan{
+			TraceID:           randomID(16),
+			SpanID:            randomID(8),
+			Name:              "container " + e.ID,
+			Kind:              1,
+			StartTimeUnixNano: unixNano(e.Timestamp),
+			Attributes:        []otlpAttribute{stringAttribute("container.id", e.ID)},
+		}
+	case e.Type == "exit" && e.Pid == runtime.InitProcessID:
+		span, ok := x.started[e.ID]
+		if !ok {
+			return
+		}
+		delete(x.started, e.ID)
+		span.EndTimeUnixNano = unixNano(e.Timestamp)
+		span.Attributes = append(span.Attributes, stringAttribute("process.exit_status", strconv.Itoa(e.Status)))
+		x.spans = append(x.spans, span)
+	}
+}
+
+// flush exports the buffered records, records that fail to export are dropped
+func (x *otlpExporter) flush() {
+	if len(x.logs) > 0 {
+		x.export("/v1/logs", map[string]interface{}{
+			"resourceLogs": []interface{}{
+				map[string]interface{}{
+					"resource":  otlpResource,
+					"scopeLogs": []interface{}{map[string]interface{}{"scope": otlpScope, "logRecords": x.logs}},
+				},
+			},
+		})
+		x.logs = nil
+	}
+	if len(x.spans) > 0 {
+		x.export("/v1/traces", map[string]interface{}{
+			"resourceSpans": []interface{}{
+				map[string]interface{}{
+					"resource":   otlpResource,
+					"scopeSpans": []interface{}{map[string]interface{}{"scope": otlpScope, "spans": x.spans}},
+				},
+			},
+		})
+		x.spans = nil
+	}
+}
+
+func (x *otlpExporter) export(path string, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		logrus.WithField("error", err).Error("containerd: encode otlp export")
+		return
+	}
+	resp, err := x.client.Post(x.endpoint+path, "application/json", bytes.NewReader(data))
+	if err != nil {
+		logrus.WithField("error", err).Warn("containerd: otlp export")
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		logrus.WithField("status", resp.Status).Warn("containerd: otlp export")
+	}
+}
+
+var (
+	otlpResource = map[string]interface{}{
+		"attributes": []otlpAttribute{stringAttribute("service.name", "containerd")},
+	}
+	otlpScope = map[string]string{
+		"name": "github.com/docker/containerd",
+	}
+)
+
+type otlpValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpLogRecord struct {
+	TimeUnixNano string          `json:"timeUnixNano"`
+	SeverityText string          `json:"severityText"`
+	Body         otlpValue       `json:"body"`
+	Attributes   []otlpAttribute `json:"attributes"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes"`
+}
+
+func stringAttribute(key, value string) otlpAttribute {
+	return otlpAttribute{
+		Key:   key,
+		Value: otlpValue{StringValue: value},
+	}
+}
+
+// unixNano returns t in nanoseconds as a string which is how OTLP encodes 64 bit
+// integers in json
+func unixNano(t time.Time) string {
+	return fmt.Sprint(t.UnixNano())
+}
+
+func randomID(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
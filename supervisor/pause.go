@@ -0,0 +1,83 @@
+package supervisor
+
+import (
+	"time"
+
+	"github.com/docker/containerd/runtime"
+)
+
+type PauseTask struct {
+	s *Supervisor
+}
+
+// Handle freezes the processes of the container e.ID and emits a pause event.
+// Pausing a paused container does nothing.
+func (h *PauseTask) Handle(e *Task) error {
+	i, ok := h.s.containers[e.ID]
+	if !ok {
+		return ErrContainerNotFound
+	}
+	return h.s.pauseContainer(i.container, e.CorrelationID)
+}
+
+type ResumeTask struct {
+	s *Supervisor
+}
+
+// Handle thaws the processes of the container e.ID and emits a resume event.
+// Resuming a running container does nothing.
+func (h *ResumeTask) Handle(e *Task) error {
+	i, ok := h.s.containers[e.ID]
+	if !ok {
+		return ErrContainerNotFound
+	}
+	return h.s.resumeContainer(i.container, e.CorrelationID)
+}
+
+func (s *Supervisor) pauseContainer(container runtime.Container, correlationID string) error {
+	switch container.State() {
+	case runtime.Paused:
+		return nil
+	case runtime.Stopped:
+		return runtime.ErrContainerExited
+	}
+	if err := container.Pause(s.pauseTimeout); err != nil {
+		if err == runtime.ErrTimeout {
+			containerLog(container.ID()).Warn("containerd: pause timed out, container was thawed")
+			return err
+		}
+		containerLog(container.ID()).WithField("error", err).Error("containerd: pause container")
+		return ErrUnknownContainerStatus
+	}
+	s.updateSnapshot(container, runtime.Paused)
+	s.notifySubscribers(withCorrelationID(Event{
+		ID:        container.ID(),
+		Type:      "pause",
+		Timestamp: time.Now(),
+	}, correlationID))
+	return nil
+}
+
+func (s *Supervisor) resumeContainer(container runtime.Container, correlationID string) error {
+	switch container.State() {
+	case runtime.Running:
+		return nil
+	case runtime.Stopped:
+		return runtime.ErrContainerExited
+	}
+	if err := container.Resume(s.pauseTimeout); err != nil {
+		if err == runtime.ErrTimeout {
+			containerLog(container.ID()).Warn("containerd: resume timed out, container was thawed")
+			return err
+		}
+		containerLog(container.ID()).WithField("error", err).Error("containerd: resume container")
+		return ErrUnknownContainerStatus
+	}
+	s.updateSnapshot(container, runtime.Running)
+	s.notifySubscribers(withCorrelationID(Event{
+		ID:        container.ID(),
+		Type:      "resume",
+		Timestamp: time.Now(),
+	}, correlationID))
+	return nil
+}
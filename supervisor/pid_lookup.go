@@ -0,0 +1,85 @@
+package supervisor
+
+import (
+	"strconv"
+
+	"github.com/docker/containerd/runtime"
+)
+
+// systemPidKey is the metadata key holding the system pid of a started process
+const systemPidKey = "systemPid"
+
+// PidLifecycle is the container and process that a system pid belongs to and the
+// events of the process
+type PidLifecycle struct {
+	ID     string
+	Pid    string
+	Events []Event
+}
+
+type PidLookupTask struct {
+	s *Supervisor
+}
+
+// Handle finds the process with the system pid e.SystemPid and returns its container,
+// process id and lifecycle events.  The process is found from the start events in the
+// event log and otherwise from the processes of the running containers.  As system
+// pids are reused the most recently started process with the pid is returned.
+func (h *PidLookupTask) Handle(e *Task) error {
+	pid := strconv.Itoa(e.SystemPid)
+	h.s.eventLock.RLock()
+	defer h.s.eventLock.RUnlock()
+	for i := len(h.s.eventLog) - 1; i >= 0; i-- {
+		ev := h.s.eventLog[i]
+		if ev.Metadata[systemPidKey] != pid {
+			continue
+		}
+		id, p := ev.ID, ev.Pid
+		if p == "" {
+			p = runtime.InitProcessID
+		}
+		e.PidLifecycle = &PidLifecycle{
+			ID:     id,
+			Pid:    p,
+			Events: h.s.processEvents(i, id, p),
+		}
+		return nil
+	}
+	for id, i := range h.s.containers {
+		processes, err := i.container.Processes()
+		if err != nil {
+			continue
+		}
+		for _, p := range processes {
+			if p.SystemPid() == e.SystemPid {
+				e.PidLifecycle = &PidLifecycle{
+					ID:     id,
+					Pid:    p.ID(),
+					Events: h.s.processEvents(0, id, p.ID()),
+				}
+				return nil
+			}
+		}
+	}
+	return ErrProcessNotFound
+}
+
+// processEvents returns the events of the process from the event log starting at
+// first up to the exit of the process, eventLock must be held
+func (s *Supervisor) processEvents(first int, id, pid string) []Event {
+	var out []Event
+	for _, ev := range s.eventLog[first:] {
+		if ev.ID != id {
+			continue
+		}
+		// events of the container apply to its init process
+		if ev.Pid != pid && !(ev.Pid == "" && pid == runtime.InitProcessID) {
+			continue
+		}
+		out = append(out, ev)
+		if ev.Type == "exit" {
+			break
+		}
+	}
+	return out
+}
@@ -0,0 +1,48 @@
+package supervisor
+
+import (
+	"strings"
+
+	"github.com/opencontainers/specs"
+)
+
+// SpecPolicy decides if a container may be started with its OCI spec
+type SpecPolicy interface {
+	// Evaluate returns false and the reasons if the container is not allowed
+	// to start with the spec
+	Evaluate(id string, spec *specs.LinuxSpec) (allowed bool, reasons []string)
+}
+
+// WithSpecPolicy sets the policy that a container's spec is evaluated against
+// before the container is started
+func WithSpecPolicy(p SpecPolicy) Option {
+	return func(s *Supervisor) {
+		s.specPolicy = p
+	}
+}
+
+// PolicyError is returned when a container's spec is denied by the spec policy
+type PolicyError struct {
+	Reasons []string
+}
+
+func (e *PolicyError) Error() string {
+	if len(e.Reasons) == 0 {
+		return "containerd: container denied by policy"
+	}
+	return "containerd: container denied by policy: " + strings.Join(e.Reasons, "; ")
+}
+
+// evaluateSpec returns a PolicyError if the supervisor has a spec policy that
+// denies the spec
+func (s *Supervisor) evaluateSpec(id string, spec *specs.LinuxSpec) error {
+	if s.specPolicy == nil {
+		return nil
+	}
+	if allowed, reasons := s.specPolicy.Evaluate(id, spec); !allowed {
+		return &PolicyError{
+			Reasons: reasons,
+		}
+	}
+	return nil
+}
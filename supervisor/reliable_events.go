@@ -0,0 +1,88 @@
+package supervisor
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+)
+
+// OverflowPolicy decides what happens when a reliable subscriber's buffer is full
+type OverflowPolicy string
+
+const (
+	// OverflowBlock blocks the delivery of events to all subscribers until the
+	// subscriber has room for the event
+	OverflowBlock OverflowPolicy = "block"
+	// OverflowDisconnect closes the subscriber's channel and sends a
+	// subscriber-dropped event to the remaining subscribers
+	OverflowDisconnect OverflowPolicy = "disconnect"
+)
+
+type reliableSubscriber struct {
+	policy OverflowPolicy
+	// done is closed when the subscriber unsubscribes to release a blocked delivery
+	done chan struct{}
+	once sync.Once
+	// dropped is set once the subscriber overflowed, it is only accessed with seqLock held
+	dropped bool
+}
+
+func (r *reliableSubscriber) close() {
+	r.once.Do(func() {
+		close(r.done)
+	})
+}
+
+// EventsReliable returns an event channel the same as Events that never silently
+// drops events.  The channel buffers up to size events and once it is full the policy
+// decides if the delivery of events blocks or the channel is closed.  When a channel
+// is closed a subscriber-dropped event holding the sequence number of the last event
+// sent to the channel is sent to the remaining subscribers, the consumer can resume
+// from that event with EventsFromSeq.
+func (s *Supervisor) EventsReliable(from time.Time, size int, policy OverflowPolicy, opts ...EventsOption) (chan Event, error) {
+	if policy != OverflowBlock && policy != OverflowDisconnect {
+		return nil, ErrUnknownOverflowPolicy
+	}
+	if size <= 0 {
+		size = defaultBufferSize
+	}
+	return s.subscribe(from, true, append(opts, func(c *eventsConfig) {
+		c.buffer = size
+		c.overflow = policy
+	})...)
+}
+
+// sendReliable sends the event to a reliable subscriber and returns false if the
+// subscriber must be dropped
+func sendReliable(sub chan Event, r *reliableSubscriber, e Event) bool {
+	select {
+	case sub <- e:
+		return true
+	default:
+	}
+	if r.policy == OverflowDisconnect {
+		return false
+	}
+	logrus.WithField("event", e.Type).Warn("containerd: event delivery blocked by subscriber")
+	select {
+	case sub <- e:
+	case <-r.done:
+	}
+	return true
+}
+
+// dropSubscriber closes the channel of a reliable subscriber that overflowed.  lastSeq
+// is the sequence number of the last event sent to the subscriber.
+func (s *Supervisor) dropSubscriber(sub chan Event, lastSeq uint64) {
+	logrus.WithField("lastSeq", lastSeq).Warn("containerd: dropping event subscriber that overflowed")
+	s.Unsubscribe(sub)
+	s.notifySubscribers(Event{
+		Type:      "subscriber-dropped",
+		Timestamp: time.Now(),
+		Metadata: map[string]string{
+			"lastSeq": strconv.FormatUint(lastSeq, 10),
+		},
+	})
+}
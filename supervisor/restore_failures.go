@@ -0,0 +1,75 @@
+package supervisor
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/Sirupsen/logrus"
+)
+
+// corruptDir is the directory in a state directory that containers which failed to
+// restore are moved to
+const corruptDir = "corrupt"
+
+// RestoreSummary is the number of containers that were restored and that failed to
+// restore when the supervisor was created
+type RestoreSummary struct {
+	Restored int
+	Failed   int
+}
+
+// WithStrictRestore fails the creation of the supervisor when a container fails to
+// restore instead of skipping the container.
+func WithStrictRestore(strict bool) Option {
+	return func(s *Supervisor) {
+		s.strictRestore = strict
+	}
+}
+
+// WithMoveCorrupt moves the state directories of containers that fail to restore to
+// the corrupt directory of their state directory so they are not restored again.
+func WithMoveCorrupt(move bool) Option {
+	return func(s *Supervisor) {
+		s.moveCorrupt = move
+	}
+}
+
+// RestoreSummary returns the result of restoring the containers in the state
+// directories when the supervisor was created
+func (s *Supervisor) RestoreSummary() RestoreSummary {
+	return s.restoreSummary
+}
+
+// restoreFailed quarantines a container that failed to restore so that the rest of
+// the containers are restored, unless the restore is strict
+func (s *Supervisor) restoreFailed(root, id string, err error) error {
+	if s.strictRestore {
+		return err
+	}
+	s.restoreSummary.Failed++
+	s.quarantined[id] = err
+	log := containerLog(id).WithField("error", err)
+	if !s.moveCorrupt {
+		log.Error("containerd: quarantine container that failed to restore")
+		return nil
+	}
+	dest := filepath.Join(root, corruptDir, id)
+	if merr := os.MkdirAll(filepath.Dir(dest), 0755); merr != nil {
+		log.WithField("moveError", merr).Error("containerd: quarantine container that failed to restore")
+		return nil
+	}
+	if merr := os.Rename(filepath.Join(root, id), dest); merr != nil {
+		log.WithField("moveError", merr).Error("containerd: quarantine container that failed to restore")
+		return nil
+	}
+	delete(s.stateDirs, id)
+	log.WithField("path", dest).Error("containerd: moved container that failed to restore")
+	return nil
+}
+
+func (s *Supervisor) logRestoreSummary() {
+	logrus.WithFields(logrus.Fields{
+		"restored": s.restoreSummary.Restored,
+		"failed":   s.restoreSummary.Failed,
+	}).Info("containerd: containers restored")
+}
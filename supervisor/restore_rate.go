@@ -0,0 +1,29 @@
+package supervisor
+
+import "time"
+
+// WithRestoreRate limits the number of containers restored per second on startup so
+// that restoring many containers does not overload the host.  Zero means unlimited.
+func WithRestoreRate(perSecond float64) Option {
+	return func(s *Supervisor) {
+		s.restoreRate = perSecond
+	}
+}
+
+// restoreLimiter returns a function that blocks until the next container can be
+// restored at the restore rate
+func (s *Supervisor) restoreLimiter() func() {
+	if s.restoreRate <= 0 {
+		return func() {}
+	}
+	var (
+		interval = time.Duration(float64(time.Second) / s.restoreRate)
+		next     time.Time
+	)
+	return func() {
+		if d := next.Sub(time.Now()); d > 0 {
+			time.Sleep(d)
+		}
+		next = time.Now().Add(interval)
+	}
+}
@@ -0,0 +1,47 @@
+package supervisor
+
+import (
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/containerd/runtime"
+)
+
+// WithRestoreRetry retries loading a container's state during restore up to attempts
+// times, waiting backoff before the first retry and doubling it before each following
+// retry.
+func WithRestoreRetry(attempts int, backoff time.Duration) Option {
+	return func(s *Supervisor) {
+		s.restoreAttempts = attempts
+		s.restoreBackoff = backoff
+	}
+}
+
+// loadContainer loads the container's state retrying transient failures
+func (s *Supervisor) loadContainer(root, id string) (runtime.Container, error) {
+	backoff := s.restoreBackoff
+	for i := 0; ; i++ {
+		container, err := runtime.Load(root, id)
+		if err == nil || i >= s.restoreAttempts {
+			return container, err
+		}
+		containerLog(id).WithFields(logrus.Fields{
+			"error":   err,
+			"attempt": i + 1,
+		}).Warn("containerd: load container state, retrying")
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// Quarantined returns the containers that could not be loaded during restore and the
+// error that was returned when they were last loaded.  Their state directories are
+// left in place so that they can be inspected or restored by a later start, unless
+// they are moved to the corrupt directory.
+func (s *Supervisor) Quarantined() map[string]error {
+	out := make(map[string]error, len(s.quarantined))
+	for id, err := range s.quarantined {
+		out[id] = err
+	}
+	return out
+}
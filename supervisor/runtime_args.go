@@ -0,0 +1,55 @@
+package supervisor
+
+import (
+	"strings"
+
+	"github.com/Sirupsen/logrus"
+)
+
+// WithAllowedRuntimeArgs sets the runtime flags, i.e. "--systemd-cgroup", that can be
+// passed to the runtime for a container.  Flags taking a value are allowed in the
+// "--flag=value" form.  By default no runtime args are allowed.
+func WithAllowedRuntimeArgs(flags ...string) Option {
+	return func(s *Supervisor) {
+		s.allowedRuntimeArgs = make(map[string]bool)
+		for _, f := range flags {
+			s.allowedRuntimeArgs[f] = true
+		}
+	}
+}
+
+// WithRuntimes sets the OCI runtimes that containers can select by name, mapped to
+// the path of their binary.  Containers that do not select a runtime are run by
+// runtime.DefaultRuntime.
+func WithRuntimes(runtimes map[string]string) Option {
+	return func(s *Supervisor) {
+		s.runtimes = runtimes
+	}
+}
+
+// containerRuntime returns the runtime binary selected by the start task
+func (s *Supervisor) containerRuntime(e *Task) (string, error) {
+	if e.Runtime == "" {
+		return "", nil
+	}
+	path, ok := s.runtimes[e.Runtime]
+	if !ok {
+		logrus.WithField("runtime", e.Runtime).Warn("containerd: unknown runtime")
+		return "", ErrUnknownRuntime
+	}
+	return path, nil
+}
+
+func (s *Supervisor) validateRuntimeArgs(args []string) error {
+	for _, a := range args {
+		flag := a
+		if i := strings.Index(a, "="); i >= 0 {
+			flag = a[:i]
+		}
+		if !strings.HasPrefix(flag, "-") || !s.allowedRuntimeArgs[flag] {
+			logrus.WithField("arg", a).Warn("containerd: runtime argument not allowed")
+			return ErrRuntimeArgNotAllowed
+		}
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package supervisor
+
+import (
+	"strconv"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/containerd/eventloop"
+	"github.com/docker/containerd/runtime"
+)
+
+const defaultShutdownTimeout = 10 * time.Second
+
+// lifecycleTasks are still accepted by the supervisor after Stop has been called
+// and are handled ahead of the other tasks queued in the event loop
+var lifecycleTasks = map[TaskType]bool{
+	ExitTaskType:     true,
+	ExecExitTaskType: true,
+	DeleteTaskType:   true,
+	OOMTaskType:      true,
+}
+
+// WithShutdownTimeout sets how long Stop waits for queued tasks to be handled
+func WithShutdownTimeout(d time.Duration) Option {
+	return func(s *Supervisor) {
+		s.shutdownTimeout = d
+	}
+}
+
+// drainEvent is sent to the event loop to detect when all the tasks queued
+// before it have been handled
+type drainEvent struct {
+	done chan struct{}
+}
+
+func (e *drainEvent) Handle() {
+	close(e.done)
+}
+
+// drain waits for all the tasks currently queued in the event loop to be handled
+func (s *Supervisor) drain(timeout time.Duration) error {
+	e := &drainEvent{
+		done: make(chan struct{}),
+	}
+	if err := s.el.Send(e); err != nil {
+		return err
+	}
+	select {
+	case <-e.done:
+		return nil
+	case <-time.After(timeout):
+		return errDrainTimeout
+	}
+}
+
+// sendLifecycleTask sends a lifecycle task triggered by the task being handled on
+// the event loop.  Once Close has started draining the loop it no longer accepts
+// tasks so the task is handled inline, the cleanup of an exit drained by Close is
+// not lost.
+func (s *Supervisor) sendLifecycleTask(evt *Task) {
+	TasksCounter.Inc(1)
+	t := &commonTask{data: evt, sv: s}
+	if err := s.el.SendPriority(t); err != nil {
+		if err != eventloop.ErrLoopClosed {
+			evt.Err <- err
+			return
+		}
+		t.Handle()
+	}
+}
+
+// StopWithTimeout sends SIGTERM to the init process of every container, waits up to
+// d for the containers to exit and sends SIGKILL to the containers still running
+// before stopping the supervisor like Stop.  New tasks are rejected from the time it
+// is called.  A signal event is sent for every signal sent to a container.
+func (s *Supervisor) StopWithTimeout(d time.Duration) {
+	if !atomic.CompareAndSwapInt32(&s.stopping, 0, 1) {
+		return
+	}
+	events, _ := s.subscribe(time.Time{}, false)
+	running := make(map[string]bool)
+	for _, c := range s.Containers() {
+		if s.signalContainer(c.ID, syscall.SIGTERM) {
+			running[c.ID] = true
+		}
+	}
+	t := time.NewTimer(d)
+wait:
+	for len(running) > 0 {
+		select {
+		case e, ok := <-events:
+			if !ok {
+				break wait
+			}
+			if e.Type == "exit" && e.Pid == runtime.InitProcessID {
+				delete(running, e.ID)
+			}
+		case <-t.C:
+			break wait
+		}
+	}
+	t.Stop()
+	s.Unsubscribe(events)
+	for id := range running {
+		containerLog(id).Warn("containerd: container did not exit after SIGTERM")
+		s.signalContainer(id, syscall.SIGKILL)
+	}
+	s.stop()
+}
+
+// signalContainer sends the signal to the container's init process through the
+// event loop, while the supervisor is stopping, and returns true if the signal was
+// sent.  A container that has exited is never reported as signalled.
+func (s *Supervisor) signalContainer(id string, sig syscall.Signal) bool {
+	e := NewTask(SignalTaskType)
+	e.ID = id
+	e.Pid = runtime.InitProcessID
+	e.Signal = sig
+	e.reportExited = true
+	s.sendTask(e)
+	if err := <-e.Err; err != nil {
+		if err != runtime.ErrContainerExited {
+			containerLog(id).WithFields(logrus.Fields{
+				"error":  err,
+				"signal": sig,
+			}).Error("containerd: signal container on shutdown")
+		}
+		return false
+	}
+	s.notifySubscribers(Event{
+		ID:        id,
+		Type:      "signal",
+		Timestamp: time.Now(),
+		Pid:       runtime.InitProcessID,
+		Metadata: map[string]string{
+			"signal": strconv.Itoa(int(sig)),
+		},
+	})
+	return true
+}
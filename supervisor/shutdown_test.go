@@ -0,0 +1,31 @@
+package supervisor
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestLifecycleTaskHandledAfterClose(t *testing.T) {
+	dir, err := ioutil.TempDir("", "containerd-shutdown")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	s, err := New(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Start()
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	// a task sent by a handler while Close drains the loop is handled inline
+	// rather than failing with eventloop.ErrLoopClosed
+	e := NewTask(DeleteTaskType)
+	e.ID = "missing"
+	s.sendLifecycleTask(e)
+	if err := <-e.Err; err != nil {
+		t.Fatalf("expected the delete task to be handled but received %v", err)
+	}
+}
@@ -1,5 +1,20 @@
 package supervisor
 
+import (
+	"syscall"
+
+	"github.com/docker/containerd/runtime"
+)
+
+// WithIgnoreSignalExited makes signals sent to a container whose init process has
+// exited succeed without doing anything.  By default they fail with
+// runtime.ErrContainerExited.
+func WithIgnoreSignalExited() Option {
+	return func(s *Supervisor) {
+		s.ignoreSignalExited = true
+	}
+}
+
 type SignalTask struct {
 	s *Supervisor
 }
@@ -15,8 +30,36 @@ func (h *SignalTask) Handle(e *Task) error {
 	}
 	for _, p := range processes {
 		if p.ID() == e.Pid {
-			return p.Signal(e.Signal)
+			// don't signal the pid if the exit was handled as it may have been reused
+			if h.initExited(i, processes) {
+				return h.exited(e)
+			}
+			if err := p.Signal(e.Signal); err != nil {
+				if err == syscall.ESRCH {
+					return h.exited(e)
+				}
+				return err
+			}
+			return nil
 		}
 	}
 	return ErrProcessNotFound
 }
+
+func (h *SignalTask) exited(e *Task) error {
+	if h.s.ignoreSignalExited && !e.reportExited {
+		return nil
+	}
+	return runtime.ErrContainerExited
+}
+
+// initExited returns true if the exit of the container's init process has been handled
+func (h *SignalTask) initExited(i *containerInfo, processes []runtime.Process) bool {
+	for _, p := range processes {
+		if p.ID() == runtime.InitProcessID {
+			_, ok := i.reaped[p]
+			return ok
+		}
+	}
+	return false
+}
@@ -0,0 +1,137 @@
+package supervisor
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/docker/pkg/mount"
+	"github.com/opencontainers/specs"
+)
+
+// snapshotsFile is the file in the supervisor's state directory that records the
+// snapshots mounted for containers
+const snapshotsFile = "snapshots.json"
+
+// Mount is a filesystem mount that makes up part of a snapshot
+type Mount struct {
+	Type    string
+	Source  string
+	Options []string
+}
+
+// Snapshotter provides the root filesystems of containers started from a snapshot
+type Snapshotter interface {
+	// Mounts returns the mounts for the snapshot with the key, they are applied
+	// in order on the container's rootfs
+	Mounts(key string) ([]Mount, error)
+	// Release is called after the container using the snapshot with the key is
+	// deleted and its mounts have been removed
+	Release(key string) error
+}
+
+// WithSnapshotter sets the snapshotter for containers started with a snapshot key
+func WithSnapshotter(sn Snapshotter) Option {
+	return func(s *Supervisor) {
+		s.snapshotter = sn
+	}
+}
+
+// snapshotRef is the snapshot mounted for a container
+type snapshotRef struct {
+	Key    string `json:"key"`
+	Rootfs string `json:"rootfs"`
+	Mounts int    `json:"mounts"`
+}
+
+func loadSnapshots(stateDir string) (map[string]snapshotRef, error) {
+	snapshots := make(map[string]snapshotRef)
+	if err := readStateFile(filepath.Join(stateDir, snapshotsFile), &snapshots); err != nil {
+		return nil, err
+	}
+	return snapshots, nil
+}
+
+func (s *Supervisor) saveSnapshots() error {
+	return writeStateFile(filepath.Join(s.stateDir, snapshotsFile), s.snapshots, len(s.snapshots) == 0)
+}
+
+// mountSnapshot mounts the snapshot with the key on the rootfs of the bundle
+func (s *Supervisor) mountSnapshot(id, bundle, key string) error {
+	if s.snapshotter == nil {
+		return ErrNoSnapshotter
+	}
+	rootfs, err := bundleRootfs(bundle)
+	if err != nil {
+		return err
+	}
+	mounts, err := s.snapshotter.Mounts(key)
+	if err != nil {
+		return err
+	}
+	ref := snapshotRef{
+		Key:    key,
+		Rootfs: rootfs,
+	}
+	for _, m := range mounts {
+		if err := mount.ForceMount(m.Source, rootfs, m.Type, strings.Join(m.Options, ",")); err != nil {
+			unmountSnapshot(ref)
+			return err
+		}
+		ref.Mounts++
+	}
+	s.snapshots[id] = ref
+	if err := s.saveSnapshots(); err != nil {
+		delete(s.snapshots, id)
+		unmountSnapshot(ref)
+		return err
+	}
+	return nil
+}
+
+// releaseSnapshot unmounts the container's snapshot, if it has one, and releases it
+func (s *Supervisor) releaseSnapshot(id string) error {
+	ref, ok := s.snapshots[id]
+	if !ok {
+		return nil
+	}
+	if err := unmountSnapshot(ref); err != nil {
+		return err
+	}
+	delete(s.snapshots, id)
+	if err := s.saveSnapshots(); err != nil {
+		return err
+	}
+	if s.snapshotter == nil {
+		return ErrNoSnapshotter
+	}
+	return s.snapshotter.Release(ref.Key)
+}
+
+// unmountSnapshot removes the mounts of the snapshot from the rootfs, the last mount first
+func unmountSnapshot(ref snapshotRef) error {
+	for i := 0; i < ref.Mounts; i++ {
+		if err := mount.Unmount(ref.Rootfs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bundleRootfs returns the path to the rootfs in the bundle's spec
+func bundleRootfs(bundle string) (string, error) {
+	f, err := os.Open(filepath.Join(bundle, "config.json"))
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	var spec specs.LinuxSpec
+	if err := json.NewDecoder(f).Decode(&spec); err != nil {
+		return "", err
+	}
+	if filepath.IsAbs(spec.Root.Path) {
+		return spec.Root.Path, nil
+	}
+	return filepath.Join(bundle, spec.Root.Path), nil
+}
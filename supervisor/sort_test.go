@@ -57,6 +57,18 @@ func (p *testProcess) Close() error {
 	return nil
 }
 
+func (p *testProcess) Invocation() (*runtime.Invocation, error) {
+	return nil, nil
+}
+
+func (p *testProcess) MarkExitDelivered() error {
+	return nil
+}
+
+func (p *testProcess) ExitDelivered() bool {
+	return false
+}
+
 func TestSortProcesses(t *testing.T) {
 	p := []runtime.Process{
 		&testProcess{"ls"},
@@ -0,0 +1,224 @@
+package supervisor
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/Sirupsen/logrus"
+)
+
+const (
+	spoolDir            = "spools"
+	defaultSpoolMaxSize = 1024 // number of events kept in a spool
+)
+
+// Spool is a durable event subscriber.  Events are persisted to a file in the
+// supervisor's state directory until they are acknowledged by the consumer so
+// that no events are missed while the consumer is disconnected.  When the spool
+// is full the oldest events are dropped.
+type Spool struct {
+	name    string
+	path    string
+	maxSize int
+	c       chan struct{}
+
+	m       sync.Mutex
+	f       *os.File
+	pending []Event
+}
+
+// Name returns the name of the spool
+func (s *Spool) Name() string {
+	return s.name
+}
+
+// C returns a channel that receives a value when new events are added to the spool
+func (s *Spool) C() <-chan struct{} {
+	return s.c
+}
+
+// Pending returns the events that have not been acknowledged, oldest first
+func (s *Spool) Pending() []Event {
+	s.m.Lock()
+	defer s.m.Unlock()
+	out := make([]Event, len(s.pending))
+	copy(out, s.pending)
+	return out
+}
+
+// Ack removes the n oldest events from the spool after they have been processed
+// by the consumer
+func (s *Spool) Ack(n int) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+	if n > len(s.pending) {
+		n = len(s.pending)
+	}
+	s.pending = s.pending[n:]
+	return s.rewrite()
+}
+
+func (s *Spool) add(e Event) error {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.pending = append(s.pending, e)
+	if len(s.pending) > s.maxSize {
+		logrus.WithField("spool", s.name).Warn("containerd: spool full, dropping oldest event")
+		s.pending = s.pending[len(s.pending)-s.maxSize:]
+		if err := s.rewrite(); err != nil {
+			return err
+		}
+	} else if err := json.NewEncoder(s.f).Encode(e); err != nil {
+		return err
+	}
+	select {
+	case s.c <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// rewrite replaces the spool file with the pending events
+func (s *Spool) rewrite() error {
+	tmp := s.path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	for _, e := range s.pending {
+		if err := enc.Encode(e); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		f.Close()
+		return err
+	}
+	s.f.Close()
+	s.f = f
+	return nil
+}
+
+func openSpool(path string, maxSize int) (*Spool, error) {
+	if maxSize <= 0 {
+		maxSize = defaultSpoolMaxSize
+	}
+	s := &Spool{
+		name:    strings.TrimSuffix(filepath.Base(path), ".log"),
+		path:    path,
+		maxSize: maxSize,
+		c:       make(chan struct{}, 1),
+	}
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	s.f = f
+	dec := json.NewDecoder(bufio.NewReader(f))
+	for dec.More() {
+		var e Event
+		if err := dec.Decode(&e); err != nil {
+			// keep the events read so far, the rest of the file is rewritten
+			logrus.WithFields(logrus.Fields{"spool": s.name, "error": err}).Warn("containerd: read spool")
+			break
+		}
+		s.pending = append(s.pending, e)
+	}
+	if len(s.pending) > maxSize {
+		s.pending = s.pending[len(s.pending)-maxSize:]
+	}
+	if err := s.rewrite(); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// SubscribeDurable returns the spool with the provided name, creating it if it does
+// not exist.  The spool receives all events until it is removed with RemoveDurable,
+// including across restarts of the supervisor.  maxSize is the maximum number of
+// unacknowledged events kept in a new spool, spools reopened after a restart use
+// the default size until they are subscribed to again.
+func (s *Supervisor) SubscribeDurable(name string, maxSize int) (*Spool, error) {
+	if name == "" || strings.ContainsAny(name, "/\x00") || name == "." || name == ".." {
+		return nil, ErrInvalidSpoolName
+	}
+	s.spoolLock.Lock()
+	defer s.spoolLock.Unlock()
+	if sp, ok := s.spools[name]; ok {
+		if maxSize > 0 {
+			sp.m.Lock()
+			sp.maxSize = maxSize
+			sp.m.Unlock()
+		}
+		return sp, nil
+	}
+	if err := os.MkdirAll(filepath.Join(s.stateDir, spoolDir), 0755); err != nil {
+		return nil, err
+	}
+	sp, err := openSpool(filepath.Join(s.stateDir, spoolDir, name+".log"), maxSize)
+	if err != nil {
+		return nil, err
+	}
+	s.spools[name] = sp
+	return sp, nil
+}
+
+// RemoveDurable stops delivering events to the spool and removes its file
+func (s *Supervisor) RemoveDurable(name string) error {
+	s.spoolLock.Lock()
+	sp, ok := s.spools[name]
+	delete(s.spools, name)
+	s.spoolLock.Unlock()
+	if !ok {
+		return ErrSpoolNotFound
+	}
+	sp.m.Lock()
+	defer sp.m.Unlock()
+	sp.f.Close()
+	return os.Remove(sp.path)
+}
+
+// spoolEvent adds the event to every spool.  It is called as the event is delivered
+// so that spools receive every event in sequence instead of subscribing with a
+// channel that drops events when it is full.
+func (s *Supervisor) spoolEvent(e Event) {
+	s.spoolLock.Lock()
+	defer s.spoolLock.Unlock()
+	for _, sp := range s.spools {
+		if err := sp.add(e); err != nil {
+			logrus.WithFields(logrus.Fields{"spool": sp.name, "error": err}).Error("containerd: write event to spool")
+		}
+	}
+}
+
+// restoreSpools reopens the spools left by a previous supervisor
+func (s *Supervisor) restoreSpools() error {
+	files, err := ioutil.ReadDir(filepath.Join(s.stateDir, spoolDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	s.spoolLock.Lock()
+	defer s.spoolLock.Unlock()
+	for _, fi := range files {
+		if fi.IsDir() || filepath.Ext(fi.Name()) != ".log" {
+			continue
+		}
+		sp, err := openSpool(filepath.Join(s.stateDir, spoolDir, fi.Name()), 0)
+		if err != nil {
+			return err
+		}
+		s.spools[sp.name] = sp
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package supervisor
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSpoolDropsOldestAndPersists(t *testing.T) {
+	dir, err := ioutil.TempDir("", "containerd-spool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "test.log")
+	s, err := openSpool(path, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"a", "b", "c"} {
+		if err := s.add(Event{ID: id, Type: "exit"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.Ack(1); err != nil {
+		t.Fatal(err)
+	}
+	s.f.Close()
+
+	s, err = openSpool(path, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.f.Close()
+	pending := s.Pending()
+	if len(pending) != 1 || pending[0].ID != "c" {
+		t.Fatalf("expected only event c to be pending but received %v", pending)
+	}
+}
+
+func TestSubscribeDurable(t *testing.T) {
+	dir, err := ioutil.TempDir("", "containerd-spool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	s, err := New(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sp, err := s.SubscribeDurable("test", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.notifySubscribers(Event{ID: "a", Type: "exit"})
+	select {
+	case <-sp.C():
+	case <-time.After(1 * time.Second):
+		t.Fatal("event was not added to the spool")
+	}
+	if pending := sp.Pending(); len(pending) != 1 || pending[0].ID != "a" {
+		t.Fatalf("expected event a to be pending but received %v", pending)
+	}
+	// a new supervisor reopens the spool with its pending events
+	s, err = New(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sp, err = s.SubscribeDurable("test", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pending := sp.Pending(); len(pending) != 1 || pending[0].ID != "a" {
+		t.Fatalf("expected event a to be restored but received %v", pending)
+	}
+	if err := s.RemoveDurable("test"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSpoolReceivesEveryEvent(t *testing.T) {
+	dir, err := ioutil.TempDir("", "containerd-spool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	s, err := New(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := defaultBufferSize * 2
+	sp, err := s.SubscribeDurable("test", n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.RemoveDurable("test")
+	// more events than a subscriber channel buffers are sent without reading them
+	for i := 0; i < n; i++ {
+		s.notifySubscribers(Event{ID: "a", Type: "exit"})
+	}
+	if pending := sp.Pending(); len(pending) != n {
+		t.Fatalf("expected %d events to be pending but received %d", n, len(pending))
+	}
+}
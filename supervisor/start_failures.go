@@ -0,0 +1,64 @@
+package supervisor
+
+import (
+	"sync"
+	"time"
+)
+
+// startFailuresSize is the number of start failures retained by the supervisor
+const startFailuresSize = 100
+
+// StartFailure is the reason a container failed to start
+type StartFailure struct {
+	ID        string
+	Timestamp time.Time
+	Err       string
+}
+
+// startFailures holds the most recent start failures, oldest first
+type startFailures struct {
+	m        sync.Mutex
+	failures []StartFailure
+}
+
+func (f *startFailures) add(sf StartFailure) {
+	f.m.Lock()
+	defer f.m.Unlock()
+	f.failures = append(f.failures, sf)
+	if len(f.failures) > startFailuresSize {
+		f.failures = f.failures[len(f.failures)-startFailuresSize:]
+	}
+}
+
+// StartFailures returns the retained start failures for the container with the
+// provided id, oldest first
+func (s *Supervisor) StartFailures(id string) []StartFailure {
+	s.startFailures.m.Lock()
+	defer s.startFailures.m.Unlock()
+	var out []StartFailure
+	for _, sf := range s.startFailures.failures {
+		if sf.ID == id {
+			out = append(out, sf)
+		}
+	}
+	return out
+}
+
+// startFailed records that the container failed to start and emits a start-failed event
+func (s *Supervisor) startFailed(id, correlationID string, err error) {
+	now := time.Now()
+	s.startFailures.add(StartFailure{
+		ID:        id,
+		Timestamp: now,
+		Err:       err.Error(),
+	})
+	containerLog(id).WithField("error", err).Debug("containerd: start container")
+	s.notifySubscribers(withCorrelationID(Event{
+		ID:        id,
+		Type:      "start-failed",
+		Timestamp: now,
+		Metadata: map[string]string{
+			"error": err.Error(),
+		},
+	}, correlationID))
+}
@@ -0,0 +1,57 @@
+package supervisor
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// stateDirsFile is the file in the supervisor's state directory that records the
+// containers whose state is stored in an alternate directory
+const stateDirsFile = "state-dirs.json"
+
+func loadStateDirs(stateDir string) (map[string]string, error) {
+	dirs := make(map[string]string)
+	if err := readStateFile(filepath.Join(stateDir, stateDirsFile), &dirs); err != nil {
+		return nil, err
+	}
+	return dirs, nil
+}
+
+// saveStateDirs atomically writes the alternate state directories of containers so
+// that they are restored when the supervisor restarts
+func (s *Supervisor) saveStateDirs() error {
+	return writeStateFile(filepath.Join(s.stateDir, stateDirsFile), s.stateDirs, len(s.stateDirs) == 0)
+}
+
+// readStateFile decodes the json file at path into v, a missing file leaves v unchanged
+func readStateFile(path string, v interface{}) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// writeStateFile atomically writes v as json to path or removes the file if empty is true
+func writeStateFile(path string, v interface{}, empty bool) error {
+	if empty {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
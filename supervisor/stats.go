@@ -1,6 +1,47 @@
 package supervisor
 
-import "time"
+import (
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/containerd/runtime"
+)
+
+const (
+	defaultStatsWorkers   = 4
+	defaultStatsQueueSize = 256
+)
+
+// WithStatsWorkers sets the number of workers that collect stats for stats tasks and
+// the number of stats tasks that can be queued for the workers.  Stats tasks fail
+// with ErrStatsQueueFull when the queue is full.
+func WithStatsWorkers(workers, queue int) Option {
+	return func(s *Supervisor) {
+		s.statsWorkers = workers
+		s.statsQueueSize = queue
+	}
+}
+
+// startStatsWorkers starts the workers shared by all stats tasks
+func (s *Supervisor) startStatsWorkers() {
+	if s.statsWorkers <= 0 {
+		s.statsWorkers = defaultStatsWorkers
+	}
+	if s.statsQueueSize < 0 {
+		s.statsQueueSize = 0
+	}
+	s.statsQueue = make(chan func(), s.statsQueueSize)
+	for i := 0; i < s.statsWorkers; i++ {
+		go func() {
+			for f := range s.statsQueue {
+				f()
+			}
+		}()
+	}
+}
 
 type StatsTask struct {
 	s *Supervisor
@@ -12,16 +53,67 @@ func (h *StatsTask) Handle(e *Task) error {
 	if !ok {
 		return ErrContainerNotFound
 	}
-	// TODO: use workers for this
-	go func() {
-		s, err := i.container.Stats()
-		if err != nil {
+	f := func() {
+		// the task may have waited in the queue until the client gave up
+		if err := e.Context().Err(); err != nil {
 			e.Err <- err
 			return
 		}
-		e.Err <- nil
-		e.Stat <- s
-		ContainerStatsTimer.UpdateSince(start)
-	}()
+		var once sync.Once
+		respond := func(s *runtime.Stat, err error) {
+			once.Do(func() {
+				e.Err <- err
+				if err == nil {
+					e.Stat <- s
+				}
+			})
+		}
+		collected := make(chan struct{})
+		go func() {
+			defer close(collected)
+			defer func() {
+				if r := recover(); r != nil {
+					respond(nil, statsPanic(e.ID, r))
+				}
+			}()
+			s, err := i.container.Stats()
+			if err == runtime.ErrContainerExited {
+				// return the last stats collected before the container exited
+				if history := h.s.collector.history(e.ID); len(history) > 0 {
+					s, err = history[len(history)-1], nil
+				}
+			} else if err == nil {
+				h.s.collector.observe(e.ID, s)
+			}
+			respond(s, err)
+			if err == nil {
+				ContainerStatsTimer.UpdateSince(start)
+			}
+		}()
+		select {
+		case <-collected:
+		case <-e.Context().Done():
+			// the client is answered now but the worker waits for the collection so
+			// that the number of collections is bounded by the number of workers
+			respond(nil, e.Context().Err())
+			<-collected
+		}
+	}
+	select {
+	case h.s.statsQueue <- f:
+	default:
+		containerLog(e.ID).Warn("containerd: stats queue is full")
+		return ErrStatsQueueFull
+	}
 	return errDeferedResponse
 }
+
+// statsPanic logs a panic recovered while collecting the stats of the container, so
+// that unexpected stats data does not crash the daemon, and returns it as an error
+func statsPanic(id string, r interface{}) error {
+	containerLog(id).WithFields(logrus.Fields{
+		"panic": r,
+		"stack": string(debug.Stack()),
+	}).Error("containerd: panic collecting container stats")
+	return fmt.Errorf("containerd: panic collecting container stats: %v", r)
+}
@@ -0,0 +1,58 @@
+package supervisor
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/docker/containerd/runtime"
+	"golang.org/x/net/context"
+)
+
+// blockingStatsContainer is a container whose stats are collected once release is
+// closed
+type blockingStatsContainer struct {
+	testContainer
+	collecting chan struct{}
+	release    chan struct{}
+}
+
+func (c *blockingStatsContainer) Stats() (*runtime.Stat, error) {
+	close(c.collecting)
+	<-c.release
+	return &runtime.Stat{}, nil
+}
+
+func TestStatsTaskCancelledDuringCollection(t *testing.T) {
+	dir, err := ioutil.TempDir("", "containerd-stats")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	s, err := New(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Start()
+	defer s.Close()
+	c := &blockingStatsContainer{
+		testContainer: testContainer{id: "test"},
+		collecting:    make(chan struct{}),
+		release:       make(chan struct{}),
+	}
+	defer close(c.release)
+	s.containers["test"] = &containerInfo{container: c}
+	ctx, cancel := context.WithCancel(context.Background())
+	e := NewTask(StatsTaskType)
+	e.ID = "test"
+	e.ctx = ctx
+	e.Stat = make(chan *runtime.Stat, 1)
+	if err := (&StatsTask{s}).Handle(e); err != errDeferedResponse {
+		t.Fatalf("expected the stats to be collected by a worker but received %v", err)
+	}
+	<-c.collecting
+	cancel()
+	if err := <-e.Err; err != context.Canceled {
+		t.Fatalf("expected %v but received %v", context.Canceled, err)
+	}
+}
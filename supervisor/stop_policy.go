@@ -0,0 +1,219 @@
+package supervisor
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"syscall"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/containerd/runtime"
+)
+
+// StopPolicy controls what happens to a container when the supervisor is stopped
+type StopPolicy string
+
+const (
+	// StopPolicyDefault uses the supervisor's stop policy for a container
+	StopPolicyDefault StopPolicy = ""
+	// StopPolicyLeaveRunning leaves the container running so that it can be
+	// restored when the supervisor restarts
+	StopPolicyLeaveRunning StopPolicy = "leave-running"
+	// StopPolicyKill kills the container
+	StopPolicyKill StopPolicy = "kill"
+)
+
+const (
+	// stopConfigsFile is the file in the supervisor's state directory that records
+	// the stop configuration and start order of containers
+	stopConfigsFile = "stop-configs.json"
+	// stopPoliciesFile is the file that recorded the stop policy of containers that
+	// set their own before stop configurations were added, it is read when there is
+	// no stopConfigsFile and removed once the configurations are saved
+	stopPoliciesFile = "stop-policies.json"
+)
+
+// stopConfig is the stop configuration of a container
+type stopConfig struct {
+	Policy   StopPolicy `json:"policy,omitempty"`
+	Priority int        `json:"priority,omitempty"`
+	// Seq is the order in which the container was started, it is 0 for
+	// containers started before the order was recorded
+	Seq uint64 `json:"seq,omitempty"`
+}
+
+// WithStopPolicy sets the stop policy for containers that do not set their own.
+// The default is StopPolicyLeaveRunning.
+func WithStopPolicy(p StopPolicy) Option {
+	return func(s *Supervisor) {
+		s.stopPolicy = p
+	}
+}
+
+// WithOrderedStop makes Stop kill containers one at a time, waiting for each to exit
+// before killing the next.  Containers with a higher stop priority are killed first
+// and containers with the same priority are killed in the reverse order that they
+// were started.
+func WithOrderedStop() Option {
+	return func(s *Supervisor) {
+		s.orderedStop = true
+	}
+}
+
+func loadStopConfigs(stateDir string) (map[string]stopConfig, error) {
+	configs := make(map[string]stopConfig)
+	path := filepath.Join(stateDir, stopConfigsFile)
+	if _, err := os.Stat(path); err == nil || !os.IsNotExist(err) {
+		if err := readStateFile(path, &configs); err != nil {
+			return nil, err
+		}
+		return configs, nil
+	}
+	policies := make(map[string]StopPolicy)
+	if err := readStateFile(filepath.Join(stateDir, stopPoliciesFile), &policies); err != nil {
+		return nil, err
+	}
+	for id, p := range policies {
+		configs[id] = stopConfig{Policy: p}
+	}
+	return configs, nil
+}
+
+func (s *Supervisor) saveStopConfigs() error {
+	if err := writeStateFile(filepath.Join(s.stateDir, stopConfigsFile), s.stopConfigs, len(s.stopConfigs) == 0); err != nil {
+		return err
+	}
+	return writeStateFile(filepath.Join(s.stateDir, stopPoliciesFile), nil, true)
+}
+
+// containerStopPolicy returns the effective stop policy for the container
+func (s *Supervisor) containerStopPolicy(id string) StopPolicy {
+	if c, ok := s.stopConfigs[id]; ok && c.Policy != StopPolicyDefault {
+		return c.Policy
+	}
+	if s.stopPolicy == StopPolicyDefault {
+		return StopPolicyLeaveRunning
+	}
+	return s.stopPolicy
+}
+
+// stopPolicyEvent returns, from the event loop, the containers to kill in the order
+// they are to be killed
+type stopPolicyEvent struct {
+	s    *Supervisor
+	kill chan []runtime.Container
+}
+
+func (e *stopPolicyEvent) Handle() {
+	var kill stopOrder
+	for id, i := range e.s.containers {
+		policy := e.s.containerStopPolicy(id)
+		log := containerLog(id).WithField("policy", policy)
+		if policy != StopPolicyKill {
+			log.Info("containerd: leaving container running on shutdown")
+			continue
+		}
+		log.Info("containerd: killing container on shutdown")
+		kill = append(kill, stopOrderEntry{
+			info:     i,
+			priority: e.s.stopConfigs[id].Priority,
+		})
+	}
+	sort.Sort(kill)
+	containers := make([]runtime.Container, len(kill))
+	for i, k := range kill {
+		containers[i] = k.info.container
+	}
+	e.kill <- containers
+}
+
+type stopOrderEntry struct {
+	info     *containerInfo
+	priority int
+}
+
+// stopOrder sorts containers by descending priority and then by reverse start order,
+// containers whose start order was not recorded are killed last
+type stopOrder []stopOrderEntry
+
+func (o stopOrder) Len() int {
+	return len(o)
+}
+
+func (o stopOrder) Swap(i, j int) {
+	o[i], o[j] = o[j], o[i]
+}
+
+func (o stopOrder) Less(i, j int) bool {
+	if o[i].priority != o[j].priority {
+		return o[i].priority > o[j].priority
+	}
+	return o[i].info.seq > o[j].info.seq
+}
+
+// applyStopPolicies kills the containers whose stop policy is StopPolicyKill
+func (s *Supervisor) applyStopPolicies() {
+	e := &stopPolicyEvent{
+		s:    s,
+		kill: make(chan []runtime.Container, 1),
+	}
+	if err := s.el.Send(e); err != nil {
+		logrus.WithField("error", err).Error("containerd: apply stop policies")
+		return
+	}
+	containers := <-e.kill
+	if !s.orderedStop {
+		for _, c := range containers {
+			if err := killContainer(c); err != nil {
+				containerLog(c.ID()).WithField("error", err).Error("containerd: kill container on shutdown")
+			}
+		}
+		return
+	}
+	events, _ := s.subscribe(time.Time{}, false)
+	defer s.Unsubscribe(events)
+	for _, c := range containers {
+		if err := killContainer(c); err != nil {
+			containerLog(c.ID()).WithField("error", err).Error("containerd: kill container on shutdown")
+			continue
+		}
+		if !waitForExit(events, c.ID(), s.shutdownTimeout) {
+			containerLog(c.ID()).Warn("containerd: container did not exit on shutdown")
+		}
+	}
+}
+
+// waitForExit waits up to timeout for the exit event of the container's init process,
+// it returns false if the events channel is closed before the exit
+func waitForExit(events chan Event, id string, timeout time.Duration) bool {
+	t := time.NewTimer(timeout)
+	defer t.Stop()
+	for {
+		select {
+		case e, ok := <-events:
+			if !ok {
+				return false
+			}
+			if e.Type == "exit" && e.ID == id && e.Pid == runtime.InitProcessID {
+				return true
+			}
+		case <-t.C:
+			return false
+		}
+	}
+}
+
+// killContainer sends SIGKILL to the container's init process
+func killContainer(c runtime.Container) error {
+	processes, err := c.Processes()
+	if err != nil {
+		return err
+	}
+	for _, p := range processes {
+		if p.ID() == runtime.InitProcessID {
+			return p.Signal(syscall.SIGKILL)
+		}
+	}
+	return ErrProcessNotFound
+}
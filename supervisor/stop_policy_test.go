@@ -0,0 +1,47 @@
+package supervisor
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadStopConfigsFromStopPolicies(t *testing.T) {
+	dir, err := ioutil.TempDir("", "containerd-stop")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, stopPoliciesFile), []byte(`{"test":"kill"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	s, err := New(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p := s.containerStopPolicy("test"); p != StopPolicyKill {
+		t.Fatalf("expected the stop policy %q but received %q", StopPolicyKill, p)
+	}
+	s.stopConfigs["other"] = stopConfig{Priority: 1, Seq: 7}
+	if err := s.saveStopConfigs(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, stopPoliciesFile)); !os.IsNotExist(err) {
+		t.Fatalf("expected the stop policies file to be removed but received %v", err)
+	}
+	s, err = New(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p := s.containerStopPolicy("test"); p != StopPolicyKill {
+		t.Fatalf("expected the stop policy %q after the stop configs were saved but received %q", StopPolicyKill, p)
+	}
+	// containers started after a restart are ordered after the recorded ones
+	if s.containerSeq != 7 {
+		t.Fatalf("expected the start order to continue from 7 but received %d", s.containerSeq)
+	}
+	if info := s.newContainerInfo(&testContainer{id: "other"}); info.seq != 7 {
+		t.Fatalf("expected the recorded start order 7 but received %d", info.seq)
+	}
+}
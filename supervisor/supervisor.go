@@ -8,20 +8,25 @@ import (
 	"path/filepath"
 	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/docker/containerd/chanotify"
 	"github.com/docker/containerd/eventloop"
 	"github.com/docker/containerd/runtime"
+	"golang.org/x/net/context"
 )
 
 const (
 	defaultBufferSize = 2048 // size of queue in eventloop
 )
 
+// Option configures optional behavior of the Supervisor when passed to New.
+type Option func(*Supervisor)
+
 // New returns an initialized Process supervisor.
-func New(stateDir string, oom bool) (*Supervisor, error) {
+func New(stateDir string, oom bool, opts ...Option) (*Supervisor, error) {
 	tasks := make(chan *startTask, 10)
 	if err := os.MkdirAll(stateDir, 0755); err != nil {
 		return nil, err
@@ -35,128 +40,449 @@ func New(stateDir string, oom bool) (*Supervisor, error) {
 		return nil, err
 	}
 	s := &Supervisor{
-		stateDir:    stateDir,
-		containers:  make(map[string]*containerInfo),
-		tasks:       tasks,
-		machine:     machine,
-		subscribers: make(map[chan Event]struct{}),
-		el:          eventloop.NewChanLoop(defaultBufferSize),
-		monitor:     monitor,
+		stateDir:              stateDir,
+		startTime:             time.Now(),
+		containers:            make(map[string]*containerInfo),
+		tasks:                 tasks,
+		machine:               machine,
+		subscribers:           make(map[chan Event]bool),
+		machineSubscribers:    make(map[chan Event]struct{}),
+		subscriberTypes:       make(map[chan Event]map[string]bool),
+		subscriberContainers:  make(map[chan Event]string),
+		laggingSubscribers:    make(map[chan Event]bool),
+		batchSubscribers:      make(map[chan []Event]chan Event),
+		reliableSubscribers:   make(map[chan Event]*reliableSubscriber),
+		tracked:               make(map[string]*ContainerSnapshot),
+		trackChanged:          make(chan struct{}),
+		quarantined:           make(map[string]error),
+		missingBundles:        make(map[string]MissingBundle),
+		deleteWaiters:         make(map[string][]*Task),
+		eventsByType:          make(map[string][]int),
+		spools:                make(map[string]*Spool),
+		monitor:               monitor,
+		collector:             newStatsCollector(),
+		diskFullRetryInterval: defaultDiskFullRetryInterval,
+		shutdownTimeout:       defaultShutdownTimeout,
+		captures:              make(map[runtime.Process]*outputCapture),
+		captureLimit:          defaultCaptureLimit,
+		statsQueueSize:        defaultStatsQueueSize,
+		journalBufferLimit:    defaultJournalBufferLimit,
+	}
+	s.el = eventloop.NewObservedChanLoop(defaultBufferSize, func(wait time.Duration) {
+		EventLoopLatencyTimer.Update(wait)
+		EventLoopDepthGauge.Update(int64(s.el.Len()))
+	})
+	for _, o := range opts {
+		o(s)
+	}
+	if s.stateDirs, err = loadStateDirs(stateDir); err != nil {
+		return nil, err
+	}
+	if s.stopConfigs, err = loadStopConfigs(stateDir); err != nil {
+		return nil, err
+	}
+	for _, c := range s.stopConfigs {
+		if c.Seq > s.containerSeq {
+			s.containerSeq = c.Seq
+		}
+	}
+	if s.containerCgroups, err = loadContainerCgroups(stateDir); err != nil {
+		return nil, err
+	}
+	if s.snapshots, err = loadSnapshots(stateDir); err != nil {
+		return nil, err
 	}
 	if err := setupEventLog(s); err != nil {
 		return nil, err
 	}
+	if err := s.restoreSpools(); err != nil {
+		return nil, err
+	}
+	if s.otlpEndpoint != "" {
+		events, _ := s.subscribe(time.Time{}, false)
+		go newOTLPExporter(s.otlpEndpoint).run(events)
+	}
+	s.startSinks()
+	s.startStatsWorkers()
+	if s.livenessInterval > 0 {
+		go s.probeLiveness()
+	}
 	if oom {
 		s.notifier = chanotify.New()
-		go func() {
-			for id := range s.notifier.Chan() {
-				e := NewTask(OOMTaskType)
-				e.ID = id.(string)
-				s.SendTask(e)
+		go s.oomHandler()
+	}
+	if len(s.pressureLevels) > 0 {
+		for _, l := range s.pressureLevels {
+			if _, ok := runtime.MemoryPressureLevels[l]; !ok {
+				return nil, runtime.ErrInvalidPressureLevel
 			}
-		}()
+		}
+		s.pressureNotifier = chanotify.New()
+		go s.memoryPressureHandler()
 	}
 	// register default event handlers
 	s.handlers = map[TaskType]Handler{
-		ExecExitTaskType:         &ExecExitTask{s},
-		ExitTaskType:             &ExitTask{s},
-		StartContainerTaskType:   &StartTask{s},
-		DeleteTaskType:           &DeleteTask{s},
-		GetContainerTaskType:     &GetContainersTask{s},
-		SignalTaskType:           &SignalTask{s},
-		AddProcessTaskType:       &AddProcessTask{s},
-		UpdateContainerTaskType:  &UpdateTask{s},
-		CreateCheckpointTaskType: &CreateCheckpointTask{s},
-		DeleteCheckpointTaskType: &DeleteCheckpointTask{s},
-		StatsTaskType:            &StatsTask{s},
-		UpdateProcessTaskType:    &UpdateProcessTask{s},
+		ExecExitTaskType:          &ExecExitTask{s},
+		ExitTaskType:              &ExitTask{s},
+		StartContainerTaskType:    &StartTask{s},
+		DeleteTaskType:            &DeleteTask{s},
+		GetContainerTaskType:      &GetContainersTask{s},
+		SignalTaskType:            &SignalTask{s},
+		AddProcessTaskType:        &AddProcessTask{s},
+		UpdateContainerTaskType:   &UpdateTask{s},
+		CreateCheckpointTaskType:  &CreateCheckpointTask{s},
+		DeleteCheckpointTaskType:  &DeleteCheckpointTask{s},
+		StatsTaskType:             &StatsTask{s},
+		UpdateProcessTaskType:     &UpdateProcessTask{s},
+		OOMTaskType:               &OOMTask{s},
+		DiskUsageTaskType:         &DiskUsageTask{s},
+		OOMHistoryTaskType:        &OOMHistoryTask{s},
+		MemoryPressureTaskType:    &MemoryPressureTask{s},
+		LivenessLostTaskType:      &LivenessLostTask{s},
+		EventsByTypeTaskType:      &EventsByTypeTask{s},
+		InspectTaskType:           &InspectTask{s},
+		TransactionTaskType:       &TransactionTask{s},
+		EventLogStatsTaskType:     &EventLogStatsTask{s},
+		MemoryMapTaskType:         &MemoryMapTask{s},
+		CgroupControllersTaskType: &CgroupControllersTask{s},
+		LogTailTaskType:           &LogTailTask{s},
+		FsckTaskType:              &FsckTask{s},
+		ChangelogTaskType:         &ChangelogTask{s},
+		InvocationTaskType:        &InvocationTask{s},
+		PidLookupTaskType:         &PidLookupTask{s},
+		GetHostnameTaskType:       &GetHostnameTask{s},
+		SetHostnameTaskType:       &SetHostnameTask{s},
+		ListCheckpointsTaskType:   &ListCheckpointsTask{s},
+		PauseTaskType:             &PauseTask{s},
+		ResumeTaskType:            &ResumeTask{s},
+		ResizePtyTaskType:         &ResizePtyTask{s},
 	}
+	s.applyMiddleware()
 	go s.exitHandler()
 	if err := s.restore(); err != nil {
 		return nil, err
 	}
+	if s.cgroupReaping {
+		s.reapCgroups()
+	}
 	return s, nil
 }
 
 type containerInfo struct {
 	container runtime.Container
+	// reaped holds the processes of the container whose exit has already been handled
+	reaped map[runtime.Process]struct{}
+	// seq is the order in which the container was started
+	seq uint64
+	// execLock is held for reading by execs started off the event loop and for
+	// writing when the container is deleted so that the container is not deleted
+	// in the middle of an exec
+	execLock sync.RWMutex
+	deleted  bool
+}
+
+// newContainerInfo returns the info of a container with the start order recorded
+// in its stop configuration
+func (s *Supervisor) newContainerInfo(container runtime.Container) *containerInfo {
+	return &containerInfo{
+		container: container,
+		seq:       s.stopConfigs[container.ID()].Seq,
+	}
 }
 
 func setupEventLog(s *Supervisor) error {
-	if err := readEventLog(s); err != nil {
+	if s.journalDir != "" {
+		if err := os.MkdirAll(s.journalDir, 0755); err != nil {
+			return err
+		}
+	}
+	segments, err := readEventLog(s)
+	if err != nil {
 		return err
 	}
 	logrus.WithField("count", len(s.eventLog)).Debug("containerd: read past events")
-	events := s.Events(time.Time{})
-	f, err := os.OpenFile(filepath.Join(s.stateDir, "events.log"), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0755)
+	events, _ := s.subscribe(time.Time{}, false)
+	f, err := os.OpenFile(s.journalPath(), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0755)
+	if err != nil {
+		return err
+	}
+	fi, err := f.Stat()
 	if err != nil {
+		f.Close()
 		return err
 	}
-	enc := json.NewEncoder(f)
+	s.journalEvents, s.journalDone = events, make(chan error, 1)
+	j := newJournal(f, s.journalFlushCount, s.journalFlushInterval)
+	j.path = s.journalPath()
+	j.maxSize, j.maxEvents, j.keep = s.journalMaxSize, s.journalMaxEvents, s.journalSegments
+	j.bufferLimit = s.journalBufferLimit
+	j.size = fi.Size()
+	j.segments, j.events = segments[:len(segments)-1], segments[len(segments)-1]
 	go func() {
-		for e := range events {
-			s.eventLog = append(s.eventLog, e)
-			if err := enc.Encode(e); err != nil {
-				logrus.WithField("error", err).Error("containerd: write event to journal")
-			}
-		}
+		s.journalDone <- j.run(s, events)
 	}()
 	return nil
 }
 
-func readEventLog(s *Supervisor) error {
-	f, err := os.Open(filepath.Join(s.stateDir, "events.log"))
-	if err != nil {
-		if os.IsNotExist(err) {
-			return nil
+// readEventLog reads the rotated journal segments, oldest first, and then the
+// current journal into the event log.  It returns the number of events read from
+// each segment with the current journal last.
+func readEventLog(s *Supervisor) ([]int, error) {
+	var segments []int
+	for i := s.journalSegments; i >= 1; i-- {
+		n, err := readJournalFile(s, segmentPath(s.journalPath(), i))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
 		}
-		return err
+		segments = append(segments, n)
+	}
+	n, err := readJournalFile(s, s.journalPath())
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	return append(segments, n), nil
+}
+
+// readJournalFile appends the events in the journal file at path to the event log
+// and returns the number of events read
+func readJournalFile(s *Supervisor, path string) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
 	}
 	defer f.Close()
 	dec := json.NewDecoder(f)
-	for {
+	for n := 0; ; n++ {
 		var e Event
 		if err := dec.Decode(&e); err != nil {
 			if err == io.EOF {
-				return nil
+				return n, nil
 			}
-			return err
+			return n, err
 		}
-		s.eventLog = append(s.eventLog, e)
+		// events written before sequence numbers were added are numbered as read
+		if e.Seq == 0 {
+			e.Seq = s.eventSeq + 1
+		}
+		s.eventSeq = e.Seq
+		s.appendEvent(e)
 	}
-	return nil
 }
 
 type Supervisor struct {
 	// stateDir is the directory on the system to store container runtime state information.
 	stateDir   string
 	containers map[string]*containerInfo
-	handlers   map[TaskType]Handler
-	events     chan *Task
-	tasks      chan *startTask
+	// tracked holds snapshots of the containers for readers outside of the event
+	// loop, trackChanged is closed and replaced whenever a container is removed
+	trackLock    sync.Mutex
+	tracked      map[string]*ContainerSnapshot
+	trackChanged chan struct{}
+	handlers     map[TaskType]Handler
+	events       chan *Task
+	tasks        chan *startTask
 	// we need a lock around the subscribers map only because additions and deletions from
 	// the map are via the API so we cannot really control the concurrency
 	subscriberLock sync.RWMutex
-	subscribers    map[chan Event]struct{}
-	machine        Machine
-	notifier       *chanotify.Notifier
-	el             eventloop.EventLoop
-	monitor        *Monitor
-	eventLog       []Event
+	// subscribers holds true for the subscribers that count towards maxSubscribers
+	subscribers    map[chan Event]bool
+	maxSubscribers int
+	// subscriberTypes holds the event types received by filtered subscribers
+	subscriberTypes map[chan Event]map[string]bool
+	// subscriberContainers holds the container id of subscribers to the events of
+	// a single container
+	subscriberContainers map[chan Event]string
+	limitedSubscribers   int
+	// laggingSubscribers holds the subscribers that were sent a lag warning
+	laggingSubscribers map[chan Event]bool
+	lagHighWater       float64
+	// machineSubscribers are the subscribers that receive machine-info events
+	machineSubscribers map[chan Event]struct{}
+	// batchSubscribers maps the channels returned by EventBatches to their subscription
+	batchSubscribers map[chan []Event]chan Event
+	// reliableSubscribers holds the overflow policy of subscribers that receive every
+	// event, it has its own lock as it is needed to unblock deliveries that hold
+	// subscriberLock
+	reliableLock        sync.Mutex
+	reliableSubscribers map[chan Event]*reliableSubscriber
+	// restoreAttempts and restoreBackoff configure the retries of loading container
+	// state during restore, quarantined holds the containers that failed to load
+	restoreAttempts int
+	restoreBackoff  time.Duration
+	restoreRate     float64
+	quarantined     map[string]error
+	// strictRestore fails the restore when a container fails to restore, otherwise
+	// the container is quarantined and moved to the corrupt directory if moveCorrupt
+	// is set
+	strictRestore  bool
+	moveCorrupt    bool
+	restoreSummary RestoreSummary
+	// missingBundles holds the containers not restored because of the missing bundle
+	// policy, they can be removed after restore so are guarded by missingBundleLock
+	missingBundlePolicy MissingBundlePolicy
+	missingBundleLock   sync.Mutex
+	missingBundles      map[string]MissingBundle
+	sinks               []EventSink
+	// statsQueue holds the stats collections of stats tasks for the stats workers
+	statsWorkers   int
+	statsQueueSize int
+	statsQueue     chan func()
+	// livenessInterval is the interval of the init process liveness probe,
+	// zero disables it
+	livenessInterval time.Duration
+	// initWrapper is the binary injected as the init process of containers
+	initWrapper string
+	machineLock sync.RWMutex
+	machine     Machine
+	notifier    *chanotify.Notifier
+	el          *eventloop.ChanLoop
+	monitor     *Monitor
+	// eventLock guards eventLog and its index which are appended to as events are
+	// delivered
+	eventLock    sync.RWMutex
+	eventLog     []Event
+	eventsByType map[string][]int
+	// seqLock serializes the numbering and sending of events so that subscribers
+	// receive events in sequence
+	seqLock   sync.Mutex
+	eventSeq  uint64
+	collector *statsCollector
+	// stateDirs holds the state directory of containers that are not stored in stateDir
+	stateDirs   map[string]string
+	oomDebounce time.Duration
+	// pressureLevels are the memory pressure levels watched for every container
+	// with pressureNotifier
+	pressureLevels   []string
+	pressureNotifier *chanotify.Notifier
+	pressureDebounce time.Duration
+	// maxEventSize is the maximum json encoded size of an event, zero is unlimited
+	maxEventSize int
+	// journalFlushCount and journalFlushInterval control the buffering of the event journal
+	journalFlushCount    int
+	journalFlushInterval time.Duration
+	// journalBufferLimit is the size of the unwritten events kept for the journal
+	journalBufferLimit int
+	// journalMaxSize and journalMaxEvents are the size of a journal segment before it is
+	// rotated, journalSegments is the number of rotated segments kept
+	journalMaxSize   int64
+	journalMaxEvents int
+	journalSegments  int
+	// journalEvents is the subscription of the event journal, journalDone receives
+	// the result of closing the journal once the subscription is closed
+	journalEvents chan Event
+	journalDone   chan error
+	closeOnce     sync.Once
+	closeErr      error
+	// journalDir is the directory of the event journal when it is not stateDir
+	journalDir string
+	// diskFull is set to 1 while writes to the state directory fail with ENOSPC
+	diskFull              int32
+	diskFullRetryInterval time.Duration
+	spoolLock             sync.Mutex
+	spools                map[string]*Spool
+	// stopping is set to 1 once Stop has been called
+	stopping        int32
+	shutdownTimeout time.Duration
+	// runtimes maps the names of the runtimes that containers may select to their
+	// binaries
+	runtimes map[string]string
+	// allowedRuntimeArgs are the runtime flags that containers may be created with
+	allowedRuntimeArgs map[string]bool
+	// checkpointRoots are the directories that checkpoints with a path are stored under
+	checkpointRoots []string
+	// cgroupReaping enables removing orphaned cgroups under cgroupParent at startup
+	cgroupReaping bool
+	cgroupParent  string
+	// containerCgroups holds the ids of the containers whose cgroups are reaped
+	// if they are not restored
+	containerCgroups map[string]bool
+	// unknownTaskHandler handles tasks with a type that has no registered handler
+	unknownTaskHandler Handler
+	// deleteWaiters are the delete tasks waiting for a container to be deleted
+	deleteWaiters map[string][]*Task
+	// middleware wraps all handlers, the first middleware is the outermost
+	middleware []Middleware
+	// otlpEndpoint is the collector that events are exported to
+	otlpEndpoint string
+	// stopPolicy is the default stop policy and stopConfigs holds the containers
+	// that set their own stop policy or priority
+	stopPolicy  StopPolicy
+	stopConfigs map[string]stopConfig
+	orderedStop bool
+	// containerSeq is incremented for every container started by the supervisor
+	containerSeq uint64
+	specPolicy   SpecPolicy
+	pauseTimeout time.Duration
+	// snapshots holds the snapshots mounted as the rootfs of containers
+	snapshotter Snapshotter
+	snapshots   map[string]snapshotRef
+	startTime   time.Time
+	// containerStarts and containerExits are updated atomically as containers are
+	// started by workers
+	containerStarts int64
+	containerExits  int64
+	// ignoreSignalExited makes signals to exited containers succeed
+	ignoreSignalExited bool
+	// captures holds the output capture of exec processes, captureLimit is the
+	// maximum size of each captured stream
+	captures     map[runtime.Process]*outputCapture
+	capturesLock sync.Mutex
+	captureLimit int
+	// execSlots limits the number of exec processes being started across all
+	// containers, execs wait for a slot when execQueue is set
+	execSlots     chan struct{}
+	execQueue     bool
+	startFailures startFailures
 }
 
-// Stop closes all tasks and sends a SIGTERM to each container's pid1 then waits for they to
-// terminate.  After it has handled all the SIGCHILD events it will close the signals chan
-// and exit.  Stop is a non-blocking call and will return after the containers have been signaled
+// Stop stops the supervisor from accepting new tasks, applies the stop policy of
+// each container and waits, up to the shutdown timeout, for the tasks already queued
+// in the event loop to be handled.  Tasks for process lifecycle events such as exits
+// are still accepted so that the state of containers stays consistent.
 func (s *Supervisor) Stop() {
+	if !atomic.CompareAndSwapInt32(&s.stopping, 0, 1) {
+		return
+	}
+	s.stop()
+}
+
+// stop applies the stop policies, drains the event loop and closes the tasks
+// channel once stopping has been set
+func (s *Supervisor) stop() {
+	s.applyStopPolicies()
+	if err := s.drain(s.shutdownTimeout); err != nil {
+		logrus.WithField("error", err).Warn("containerd: drain event loop")
+	}
 	// Close the tasks channel so that no new containers get started
 	close(s.tasks)
 }
 
-// Close closes any open files in the supervisor but expects that Stop has been
-// callsed so that no more containers are started.
+// Close stops the supervisor, if Stop has not been called, and drains the event
+// loop, up to the shutdown timeout, so that the tasks still queued are handled
+// and the events already in flight are written to the event journal.  It then
+// flushes, syncs and closes the journal.  It returns the error of writing the
+// journal, calling Close again returns the same error.  Tasks sent after Close
+// fail with eventloop.ErrLoopClosed.
 func (s *Supervisor) Close() error {
-	return nil
+	s.closeOnce.Do(func() {
+		s.Stop()
+		ctx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+		if err := s.el.Drain(ctx); err != nil {
+			logrus.WithField("error", err).Warn("containerd: drain event loop")
+		}
+		cancel()
+		if s.journalEvents == nil {
+			return
+		}
+		// the journal writes the events still queued in its subscription before
+		// it sees the channel closed
+		s.Unsubscribe(s.journalEvents)
+		s.closeErr = <-s.journalDone
+	})
+	return s.closeErr
 }
 
 type Event struct {
@@ -165,32 +491,199 @@ type Event struct {
 	Timestamp time.Time `json:"timestamp"`
 	Pid       string    `json:"pid,omitempty"`
 	Status    int       `json:"status,omitempty"`
+	// Seq is the position of the event in the event log, it increases by one for
+	// every event and is kept across restarts
+	Seq uint64 `json:"seq,omitempty"`
+	// Metadata holds additional event type specific information
+	Metadata map[string]string `json:"metadata,omitempty"`
+	// Machine is set on machine-info events
+	Machine *Machine `json:"machine,omitempty"`
 }
 
 // Events returns an event channel that external consumers can use to receive updates
-// on container events
-func (s *Supervisor) Events(from time.Time) chan Event {
+// on container events.  ErrTooManySubscribers is returned if the supervisor already
+// has the maximum number of subscribers.
+func (s *Supervisor) Events(from time.Time, opts ...EventsOption) (chan Event, error) {
+	return s.subscribe(from, true, opts...)
+}
+
+// EventsFromSeq returns an event channel the same as Events that first replays the
+// events after the sequence number seq.  Consumers that resume from the sequence
+// number of the last event they received do not receive any event twice.
+func (s *Supervisor) EventsFromSeq(seq uint64, opts ...EventsOption) (chan Event, error) {
+	return s.subscribeWith(func(e Event) bool { return e.Seq > seq }, true, opts...)
+}
+
+// EventsFiltered returns an event channel the same as Events that only receives events
+// of the provided types, including replayed events.  No types receives all events.
+func (s *Supervisor) EventsFiltered(from time.Time, types ...string) (chan Event, error) {
+	return s.subscribe(from, true, func(c *eventsConfig) {
+		if len(types) == 0 {
+			return
+		}
+		c.types = make(map[string]bool, len(types))
+		for _, t := range types {
+			c.types[t] = true
+		}
+	})
+}
+
+// ContainerEvents returns an event channel the same as Events that only receives the
+// events of the container id, including replayed events.  It returns
+// ErrContainerNotFound if the container does not exist.
+func (s *Supervisor) ContainerEvents(id string, from time.Time, opts ...EventsOption) (chan Event, error) {
+	s.trackLock.Lock()
+	_, ok := s.tracked[id]
+	s.trackLock.Unlock()
+	if !ok {
+		return nil, ErrContainerNotFound
+	}
+	return s.subscribe(from, true, append(opts, func(c *eventsConfig) {
+		c.container = id
+	})...)
+}
+
+// subscribe returns a new event channel, limited subscribers count towards the
+// maximum number of subscribers
+func (s *Supervisor) subscribe(from time.Time, limited bool, opts ...EventsOption) (chan Event, error) {
+	var replay func(Event) bool
+	if !from.IsZero() {
+		replay = func(e Event) bool { return e.Timestamp.After(from) }
+	}
+	return s.subscribeWith(replay, limited, opts...)
+}
+
+// subscribeWith returns a new event channel which first receives the events in the
+// event log that replay returns true for
+func (s *Supervisor) subscribeWith(replay func(Event) bool, limited bool, opts ...EventsOption) (chan Event, error) {
+	var config eventsConfig
+	for _, o := range opts {
+		o(&config)
+	}
 	s.subscriberLock.Lock()
 	defer s.subscriberLock.Unlock()
-	c := make(chan Event, defaultBufferSize)
+	if limited && s.maxSubscribers > 0 && s.limitedSubscribers >= s.maxSubscribers {
+		logrus.WithField("max", s.maxSubscribers).Warn("containerd: too many event subscribers")
+		return nil, ErrTooManySubscribers
+	}
+	size := defaultBufferSize
+	if config.buffer > 0 {
+		size = config.buffer
+	}
+	c := make(chan Event, size)
 	EventSubscriberCounter.Inc(1)
-	s.subscribers[c] = struct{}{}
-	if !from.IsZero() {
+	s.subscribers[c] = limited
+	if config.types != nil {
+		s.subscriberTypes[c] = config.types
+		if replay != nil {
+			r := replay
+			replay = func(e Event) bool { return config.types[e.Type] && r(e) }
+		}
+	}
+	if config.container != "" {
+		s.subscriberContainers[c] = config.container
+		if replay != nil {
+			r := replay
+			replay = func(e Event) bool { return e.ID == config.container && r(e) }
+		}
+	}
+	if config.overflow != "" {
+		s.reliableLock.Lock()
+		s.reliableSubscribers[c] = &reliableSubscriber{
+			policy: config.overflow,
+			done:   make(chan struct{}),
+		}
+		s.reliableLock.Unlock()
+	}
+	if limited {
+		s.limitedSubscribers++
+	}
+	if config.machine {
+		s.machineSubscribers[c] = struct{}{}
+		c <- s.machineEvent()
+	}
+	if replay != nil {
 		// replay old event
+		s.eventLock.RLock()
 		for _, e := range s.eventLog {
-			if e.Timestamp.After(from) {
+			if replay(e) {
 				c <- e
 			}
 		}
+		s.eventLock.RUnlock()
 	}
-	return c
+	return c, nil
+}
+
+// EventsToWriter writes json encoded events to w, starting with any events after from,
+// until the returned stop function is called or a write to w fails.
+func (s *Supervisor) EventsToWriter(w io.Writer, from time.Time) (func(), error) {
+	if w == nil {
+		return nil, ErrWriterNil
+	}
+	events, err := s.Events(from)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		once = sync.Once{}
+		done = make(chan struct{})
+	)
+	stop := func() {
+		once.Do(func() {
+			close(done)
+		})
+	}
+	go func() {
+		defer s.Unsubscribe(events)
+		enc := json.NewEncoder(w)
+		for {
+			select {
+			case <-done:
+				return
+			case e, ok := <-events:
+				// the subscription was closed by the supervisor
+				if !ok {
+					stop()
+					return
+				}
+				if err := enc.Encode(e); err != nil {
+					logrus.WithField("error", err).Error("containerd: write event to writer")
+					stop()
+					return
+				}
+			}
+		}
+	}()
+	return stop, nil
 }
 
 // Unsubscribe removes the provided channel from receiving any more events
 func (s *Supervisor) Unsubscribe(sub chan Event) {
+	// release a delivery blocked on the subscriber before waiting for the lock
+	s.reliableLock.Lock()
+	if r, ok := s.reliableSubscribers[sub]; ok {
+		r.close()
+	}
+	s.reliableLock.Unlock()
 	s.subscriberLock.Lock()
 	defer s.subscriberLock.Unlock()
+	// reliable subscribers that overflowed are already unsubscribed
+	limited, ok := s.subscribers[sub]
+	if !ok {
+		return
+	}
+	s.reliableLock.Lock()
+	delete(s.reliableSubscribers, sub)
+	s.reliableLock.Unlock()
+	if limited {
+		s.limitedSubscribers--
+	}
 	delete(s.subscribers, sub)
+	delete(s.subscriberTypes, sub)
+	delete(s.subscriberContainers, sub)
+	delete(s.laggingSubscribers, sub)
+	delete(s.machineSubscribers, sub)
 	close(sub)
 	EventSubscriberCounter.Dec(1)
 }
@@ -198,16 +691,60 @@ func (s *Supervisor) Unsubscribe(sub chan Event) {
 // notifySubscribers will send the provided event to the external subscribers
 // of the events channel
 func (s *Supervisor) notifySubscribers(e Event) {
+	e = s.truncateEvent(e)
+	e, dropped := s.deliver(e)
+	logEvent(e)
+	for _, sub := range dropped {
+		s.dropSubscriber(sub, e.Seq-1)
+	}
+}
+
+// deliver numbers the event, adds it to the event log and the spools and sends it
+// to the subscribers, it returns the reliable subscribers that overflowed.  The
+// event is added to the event log under the subscribers lock so that a new
+// subscriber replays every event that it does not receive.
+func (s *Supervisor) deliver(e Event) (Event, []chan Event) {
+	s.seqLock.Lock()
+	defer s.seqLock.Unlock()
 	s.subscriberLock.RLock()
 	defer s.subscriberLock.RUnlock()
+	s.eventSeq++
+	e.Seq = s.eventSeq
+	s.appendEvent(e)
+	s.spoolEvent(e)
+	var dropped []chan Event
 	for sub := range s.subscribers {
+		// filtered events must not take a slot in the subscriber's buffer
+		if types, ok := s.subscriberTypes[sub]; ok && !types[e.Type] {
+			continue
+		}
+		if id, ok := s.subscriberContainers[sub]; ok && e.ID != id {
+			continue
+		}
+		s.reliableLock.Lock()
+		r, reliable := s.reliableSubscribers[sub]
+		s.reliableLock.Unlock()
+		if reliable {
+			// a subscriber being dropped must not receive events after the one it missed
+			if !r.dropped {
+				if !sendReliable(sub, r, e) {
+					r.dropped = true
+					dropped = append(dropped, sub)
+				} else {
+					s.warnLag(sub, e.Seq)
+				}
+			}
+			continue
+		}
 		// do a non-blocking send for the channel
 		select {
 		case sub <- e:
+			s.warnLag(sub, e.Seq)
 		default:
 			logrus.WithField("event", e.Type).Warn("containerd: event not sent to subscriber")
 		}
 	}
+	return e, dropped
 }
 
 // Start is a non-blocking call that runs the supervisor for monitoring contianer processes and
@@ -221,16 +758,46 @@ func (s *Supervisor) Start() error {
 	return s.el.Start()
 }
 
-// Machine returns the machine information for which the
+// Machine returns a copy of the machine information for which the
 // supervisor is executing on.
 func (s *Supervisor) Machine() Machine {
+	s.machineLock.RLock()
+	defer s.machineLock.RUnlock()
 	return s.machine
 }
 
+// SendTaskContext sends the task to the supervisor's main event loop like SendTask.
+// A task whose context is done before it is handled fails with the context's error
+// and long running tasks such as stats and checkpoints stop when it is done.
+func (s *Supervisor) SendTaskContext(ctx context.Context, evt *Task) {
+	evt.ctx = ctx
+	s.SendTask(evt)
+}
+
 // SendTask sends the provided event the the supervisors main event loop
 func (s *Supervisor) SendTask(evt *Task) {
+	if atomic.LoadInt32(&s.stopping) == 1 && !lifecycleTasks[evt.Type] {
+		evt.Err <- errShutdown
+		return
+	}
+	s.sendTask(evt)
+}
+
+// sendTask sends the event to the event loop even if the supervisor is stopping
+func (s *Supervisor) sendTask(evt *Task) {
 	TasksCounter.Inc(1)
-	s.el.Send(&commonTask{data: evt, sv: s})
+	t := &commonTask{data: evt, sv: s}
+	send := s.el.Send
+	if lifecycleTasks[evt.Type] {
+		// lifecycle tasks are handled ahead of queries so that a flood of
+		// stats requests does not delay the cleanup of exited containers
+		send = s.el.SendPriority
+	}
+	if err := send(t); err != nil {
+		evt.Err <- err
+		return
+	}
+	EventLoopDepthGauge.Update(int64(s.el.Len()))
 }
 
 func (s *Supervisor) exitHandler() {
@@ -250,43 +817,75 @@ func (s *Supervisor) restore() error {
 	if err != nil {
 		return err
 	}
+	wait := s.restoreLimiter()
 	for _, d := range dirs {
-		if !d.IsDir() {
+		if !d.IsDir() || d.Name() == spoolDir || d.Name() == corruptDir {
 			continue
 		}
-		id := d.Name()
-		container, err := runtime.Load(s.stateDir, id)
-		if err != nil {
+		wait()
+		if err := s.restoreContainer(s.stateDir, d.Name()); err != nil {
 			return err
 		}
-		processes, err := container.Processes()
-		if err != nil {
-			return err
+	}
+	for id, root := range s.stateDirs {
+		if _, err := os.Stat(filepath.Join(root, id)); os.IsNotExist(err) {
+			containerLog(id).WithField("stateDir", root).Warn("containerd: state directory for container not found")
+			delete(s.stateDirs, id)
+			continue
 		}
-		ContainersCounter.Inc(1)
-		s.containers[id] = &containerInfo{
-			container: container,
+		wait()
+		if err := s.restoreContainer(root, id); err != nil {
+			return err
 		}
-		logrus.WithField("id", id).Debug("containerd: container restored")
-		var exitedProcesses []runtime.Process
-		for _, p := range processes {
-			if _, err := p.ExitStatus(); err == nil {
-				exitedProcesses = append(exitedProcesses, p)
-			} else {
-				if err := s.monitorProcess(p); err != nil {
-					return err
-				}
+	}
+	s.logRestoreSummary()
+	return s.saveStateDirs()
+}
+
+func (s *Supervisor) restoreContainer(root, id string) error {
+	container, err := s.loadContainer(root, id)
+	if err != nil {
+		return s.restoreFailed(root, id, err)
+	}
+	processes, err := container.Processes()
+	if err != nil {
+		return s.restoreFailed(root, id, err)
+	}
+	if s.bundleMissing(root, id, container.Path(), processes) {
+		return nil
+	}
+	created := time.Now()
+	if fi, err := os.Stat(filepath.Join(root, id, runtime.StateFile)); err == nil {
+		created = fi.ModTime()
+	}
+	ContainersCounter.Inc(1)
+	s.restoreSummary.Restored++
+	s.addContainer(container, created)
+	s.recordContainerCgroup(id)
+	s.updateSnapshot(container, container.State())
+	s.collector.add(container, 0)
+	if container.State() != runtime.Stopped {
+		s.watchMemoryPressure(container)
+	}
+	containerLog(id).Debug("containerd: container restored")
+	var exitedProcesses []runtime.Process
+	for _, p := range processes {
+		if _, err := p.ExitStatus(); err == nil {
+			exitedProcesses = append(exitedProcesses, p)
+		} else {
+			if err := s.monitorProcess(p); err != nil {
+				return err
 			}
 		}
-		if len(exitedProcesses) > 0 {
-			// sort processes so that init is fired last because that is how the kernel sends the
-			// exit events
-			sort.Sort(&processSorter{exitedProcesses})
-			for _, p := range exitedProcesses {
-				e := NewTask(ExitTaskType)
-				e.Process = p
-				s.SendTask(e)
-			}
+	}
+	if len(exitedProcesses) > 0 {
+		// sort processes so that init is fired last because that is how the kernel sends the
+		// exit events
+		sort.Sort(&processSorter{exitedProcesses})
+		for _, p := range exitedProcesses {
+			e := NewTask(ExitTaskType)
+			e.Process = p
+			s.SendTask(e)
 		}
 	}
 	return nil
@@ -0,0 +1,89 @@
+package supervisor
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+)
+
+// TailEventLog returns the last n events in the journal file at path, oldest first.
+// The file is read backwards from its end so that only its tail is read.  Lines
+// that cannot be decoded, such as a partially written last event, are skipped.
+func TailEventLog(path string, n int) ([]Event, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	var (
+		// events are collected newest first
+		events []Event
+		// rest is the start of the file's lines that continues in the block
+		// before the last block read
+		rest []byte
+		off  = fi.Size()
+	)
+	add := func(line []byte) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			return
+		}
+		var e Event
+		if err := json.Unmarshal(line, &e); err != nil {
+			return
+		}
+		events = append(events, e)
+	}
+	for off > 0 && len(events) < n {
+		size := int64(tailChunkSize)
+		if off < size {
+			size = off
+		}
+		off -= size
+		buf := make([]byte, size, size+int64(len(rest)))
+		if _, err := f.ReadAt(buf, off); err != nil {
+			return nil, err
+		}
+		lines := bytes.Split(append(buf, rest...), []byte("\n"))
+		rest = lines[0]
+		for i := len(lines) - 1; i > 0 && len(events) < n; i-- {
+			add(lines[i])
+		}
+	}
+	// the first line of the file has no newline before it
+	if off == 0 && len(events) < n {
+		add(rest)
+	}
+	for i, j := 0, len(events)-1; i < j; i, j = i+1, j-1 {
+		events[i], events[j] = events[j], events[i]
+	}
+	return events, nil
+}
+
+// TailEvents returns the last n events written to the supervisor's journal,
+// including its rotated segments, oldest first.
+func (s *Supervisor) TailEvents(n int) ([]Event, error) {
+	path := s.journalPath()
+	events, err := TailEventLog(path, n)
+	if err != nil {
+		return nil, err
+	}
+	for i := 1; i <= s.journalSegments && len(events) < n; i++ {
+		older, err := TailEventLog(segmentPath(path, i), n-len(events))
+		if err != nil {
+			if os.IsNotExist(err) {
+				break
+			}
+			return nil, err
+		}
+		events = append(older, events...)
+	}
+	return events, nil
+}
@@ -4,26 +4,49 @@ import (
 	"os"
 	"time"
 
+	"github.com/Sirupsen/logrus"
 	"github.com/docker/containerd/runtime"
 	"github.com/opencontainers/specs"
+	"golang.org/x/net/context"
 )
 
 type TaskType string
 
 const (
-	ExecExitTaskType         TaskType = "execExit"
-	ExitTaskType             TaskType = "exit"
-	StartContainerTaskType   TaskType = "startContainer"
-	DeleteTaskType           TaskType = "deleteContainerEvent"
-	GetContainerTaskType     TaskType = "getContainer"
-	SignalTaskType           TaskType = "signal"
-	AddProcessTaskType       TaskType = "addProcess"
-	UpdateContainerTaskType  TaskType = "updateContainer"
-	UpdateProcessTaskType    TaskType = "updateProcess"
-	CreateCheckpointTaskType TaskType = "createCheckpoint"
-	DeleteCheckpointTaskType TaskType = "deleteCheckpoint"
-	StatsTaskType            TaskType = "events"
-	OOMTaskType              TaskType = "oom"
+	ExecExitTaskType          TaskType = "execExit"
+	ExitTaskType              TaskType = "exit"
+	StartContainerTaskType    TaskType = "startContainer"
+	DeleteTaskType            TaskType = "deleteContainerEvent"
+	GetContainerTaskType      TaskType = "getContainer"
+	SignalTaskType            TaskType = "signal"
+	AddProcessTaskType        TaskType = "addProcess"
+	UpdateContainerTaskType   TaskType = "updateContainer"
+	UpdateProcessTaskType     TaskType = "updateProcess"
+	CreateCheckpointTaskType  TaskType = "createCheckpoint"
+	DeleteCheckpointTaskType  TaskType = "deleteCheckpoint"
+	StatsTaskType             TaskType = "events"
+	OOMTaskType               TaskType = "oom"
+	DiskUsageTaskType         TaskType = "diskUsage"
+	OOMHistoryTaskType        TaskType = "oomHistory"
+	EventsByTypeTaskType      TaskType = "eventsByType"
+	InspectTaskType           TaskType = "inspect"
+	TransactionTaskType       TaskType = "transaction"
+	EventLogStatsTaskType     TaskType = "eventLogStats"
+	MemoryMapTaskType         TaskType = "memoryMap"
+	CgroupControllersTaskType TaskType = "cgroupControllers"
+	LogTailTaskType           TaskType = "logTail"
+	FsckTaskType              TaskType = "fsck"
+	ChangelogTaskType         TaskType = "changelog"
+	InvocationTaskType        TaskType = "invocation"
+	PidLookupTaskType         TaskType = "pidLookup"
+	GetHostnameTaskType       TaskType = "getHostname"
+	SetHostnameTaskType       TaskType = "setHostname"
+	ListCheckpointsTaskType   TaskType = "listCheckpoints"
+	PauseTaskType             TaskType = "pause"
+	ResumeTaskType            TaskType = "resume"
+	ResizePtyTaskType         TaskType = "resizePty"
+	MemoryPressureTaskType    TaskType = "memoryPressure"
+	LivenessLostTaskType      TaskType = "livenessLost"
 )
 
 func NewTask(t TaskType) *Task {
@@ -36,49 +59,168 @@ func NewTask(t TaskType) *Task {
 
 type StartResponse struct {
 	Container runtime.Container
+	// Process is the started process, its ID and SystemPid identify the process
+	// in its exit event
+	Process runtime.Process
+	// Err is the error of starting the process, Container and Process are nil
+	// when it is set
+	Err error
 }
 
 type Task struct {
-	Type          TaskType
-	Timestamp     time.Time
-	ID            string
-	BundlePath    string
-	Stdout        string
-	Stderr        string
-	Stdin         string
-	Console       string
-	Pid           string
-	Status        int
-	Signal        os.Signal
-	Process       runtime.Process
-	State         runtime.State
-	ProcessSpec   *specs.Process
-	Containers    []runtime.Container
-	Checkpoint    *runtime.Checkpoint
-	Err           chan error
-	StartResponse chan StartResponse
-	Stat          chan *runtime.Stat
-	CloseStdin    bool
-	ResizeTty     bool
-	Width         int
-	Height        int
-	Labels        []string
+	Type       TaskType
+	Timestamp  time.Time
+	ID         string
+	BundlePath string
+	// StateDir overrides the supervisor's state directory for a started container
+	StateDir    string
+	Stdout      string
+	Stderr      string
+	Stdin       string
+	Console     string
+	Pid         string
+	Status      int
+	Signal      os.Signal
+	Process     runtime.Process
+	State       runtime.State
+	ProcessSpec *specs.Process
+	Containers  []runtime.Container
+	Events      []Event
+	Inspect     *ContainerInspect
+	// Transaction holds the start tasks of containers that are started together
+	Transaction        []*Task
+	TransactionResults []TransactionResult
+	EventLogStats      *EventLogStats
+	CgroupControllers  *CgroupControllers
+	LogLines           *LogLines
+	FsckProblems       []FsckProblem
+	Changelog          []StateTransition
+	Invocation         *runtime.Invocation
+	PidLifecycle       *PidLifecycle
+	Checkpoint         *runtime.Checkpoint
+	Checkpoints        []runtime.Checkpoint
+	Err                chan error
+	StartResponse      chan StartResponse
+	Stat               chan *runtime.Stat
+	DiskUsage          chan *DiskUsage
+	MemoryMap          chan *MemoryMap
+	CloseStdin         bool
+	ResizeTty          bool
+	Width              int
+	Height             int
+	Labels             []string
+	// StatsInterval is the interval for background stats collection of a
+	// started container, zero uses the supervisor default
+	StatsInterval time.Duration
+	// CorrelationID is added to the metadata of all events generated by the task
+	// and the tasks that it triggers
+	CorrelationID string
+	// EventType, From and To select the events returned by an events query
+	EventType string
+	From      time.Time
+	To        time.Time
+	// Limit is the maximum number of results returned by a query
+	Limit int
+	// Count is the number of notifications collapsed into an OOM or memory
+	// pressure task
+	Count int
+	// PressureLevel is the memory pressure level reached by the container of a
+	// memory pressure task
+	PressureLevel string
+	// Runtime is the name of the OCI runtime that runs a started container, it must
+	// be one of the supervisor's runtimes
+	Runtime string
+	// RuntimeArgs are passed to the OCI runtime for all invocations for a started
+	// container, they must be allowed by the supervisor
+	RuntimeArgs []string
+	// CheckpointPolicy controls the creation of a checkpoint that already exists
+	CheckpointPolicy CheckpointPolicy
+	// StopPolicy overrides the supervisor's stop policy for a started container
+	StopPolicy StopPolicy
+	// StopPriority orders the container when the supervisor kills containers in
+	// order on stop, higher priorities are stopped first
+	StopPriority int
+	// CaptureOutput collects the stdout and stderr of an added process, up to the
+	// supervisor's capture limit, into the metadata of its exit event
+	CaptureOutput bool
+	// Resources are the resource limits changed by an update task
+	Resources *runtime.Resources
+	// Seq is the first sequence number of the changelog entries returned
+	Seq uint64
+	// Snapshot is the key of the snapshot that is mounted as the rootfs of a started
+	// container by the supervisor's snapshotter
+	Snapshot string
+	// SystemPid is the pid on the system looked up by a pid lookup task or the init
+	// process found gone by the liveness probe
+	SystemPid int
+	// Init runs the init process of a started container under the supervisor's
+	// init wrapper
+	Init bool
+	// Hostname is the hostname set by a set hostname task or returned by a get
+	// hostname task
+	Hostname string
+	// Wait makes a delete task respond once the init process of the container has
+	// exited and the container is deleted, with the exit status in Status
+	Wait bool
+	// Force kills the init process of a container deleted by a delete task, it
+	// implies Wait
+	Force bool
+	// Repair repairs the problems found by a fsck task that are safe to repair
+	Repair bool
+	// reportExited makes a signal task fail with runtime.ErrContainerExited for an
+	// exited container even if the supervisor ignores signals to exited containers
+	reportExited bool
+	// ctx is the context that the task was sent with
+	ctx context.Context
+}
+
+// Context returns the context that the task was sent with by SendTaskContext,
+// tasks sent by SendTask are never cancelled
+func (t *Task) Context() context.Context {
+	if t.ctx == nil {
+		return context.Background()
+	}
+	return t.ctx
 }
 
 type Handler interface {
 	Handle(*Task) error
 }
 
+// HandlerFunc adapts a function to the Handler interface
+type HandlerFunc func(*Task) error
+
+func (f HandlerFunc) Handle(e *Task) error {
+	return f(e)
+}
+
+// Middleware wraps a handler to run code around the handling of tasks, such as
+// tracing or authorization.  Handlers that respond to a task asynchronously can
+// return before the task is complete.
+type Middleware func(Handler) Handler
+
 type commonTask struct {
 	data *Task
 	sv   *Supervisor
 }
 
 func (e *commonTask) Handle() {
+	// the client has given up on the task while it was queued
+	if err := e.data.Context().Err(); err != nil {
+		e.data.Err <- err
+		close(e.data.Err)
+		return
+	}
 	h, ok := e.sv.handlers[e.data.Type]
 	if !ok {
-		e.data.Err <- ErrUnknownTask
-		return
+		UnknownTasksCounter.Inc(1)
+		if e.sv.unknownTaskHandler == nil {
+			logrus.WithField("type", e.data.Type).Warn("containerd: unsupported task type")
+			e.data.Err <- ErrUnknownTaskType
+			close(e.data.Err)
+			return
+		}
+		h = e.sv.unknownTaskHandler
 	}
 	err := h.Handle(e.data)
 	if err != errDeferedResponse {
@@ -87,3 +229,36 @@ func (e *commonTask) Handle() {
 		return
 	}
 }
+
+// WithUnknownTaskHandler sets the handler for tasks with a type that has no
+// registered handler.  By default these tasks fail with ErrUnknownTaskType.
+func WithUnknownTaskHandler(h Handler) Option {
+	return func(s *Supervisor) {
+		s.unknownTaskHandler = h
+	}
+}
+
+// WithMiddleware wraps the handlers of all task types, including the unknown task
+// handler, with the middleware.  The first middleware is the outermost and sees a
+// task first.
+func WithMiddleware(m ...Middleware) Option {
+	return func(s *Supervisor) {
+		s.middleware = append(s.middleware, m...)
+	}
+}
+
+// applyMiddleware wraps the registered handlers with the supervisor's middleware
+func (s *Supervisor) applyMiddleware() {
+	wrap := func(h Handler) Handler {
+		for i := len(s.middleware) - 1; i >= 0; i-- {
+			h = s.middleware[i](h)
+		}
+		return h
+	}
+	for t, h := range s.handlers {
+		s.handlers[t] = wrap(h)
+	}
+	if s.unknownTaskHandler != nil {
+		s.unknownTaskHandler = wrap(s.unknownTaskHandler)
+	}
+}
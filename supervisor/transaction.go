@@ -0,0 +1,96 @@
+package supervisor
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/docker/containerd/runtime"
+)
+
+// TransactionResult is the result of a single start operation of a transaction
+type TransactionResult struct {
+	ID        string
+	Container runtime.Container
+	Err       error
+}
+
+type TransactionTask struct {
+	s *Supervisor
+}
+
+// Handle starts all the containers in e.Transaction or none of them.  If any container
+// fails to be created the already created ones are removed, if any container fails to
+// start the started ones are killed and removed through their exit events.  The result of
+// every operation is returned in e.TransactionResults.
+func (h *TransactionTask) Handle(e *Task) error {
+	start := time.Now()
+	if atomic.LoadInt32(&h.s.stopping) == 1 {
+		return errShutdown
+	}
+	seen := make(map[string]bool)
+	for _, t := range e.Transaction {
+		if _, ok := h.s.containers[t.ID]; ok || seen[t.ID] {
+			return ErrContainerExists
+		}
+		seen[t.ID] = true
+	}
+	var created []runtime.Container
+	for _, t := range e.Transaction {
+		c, err := h.s.createContainer(t)
+		if err != nil {
+			h.s.startFailed(t.ID, t.CorrelationID, err)
+			for _, c := range created {
+				h.s.removeContainer(c)
+			}
+			return err
+		}
+		created = append(created, c)
+	}
+	var tasks []*startTask
+	for i, t := range e.Transaction {
+		st := newStartTask(t, created[i])
+		st.Err = make(chan error, 1)
+		st.StartResponse = make(chan StartResponse, 1)
+		if st.CorrelationID == "" {
+			st.CorrelationID = e.CorrelationID
+		}
+		tasks = append(tasks, st)
+		h.s.tasks <- st
+	}
+	go func() {
+		var (
+			failed  error
+			results = make([]TransactionResult, len(tasks))
+		)
+		for i, st := range tasks {
+			results[i] = TransactionResult{
+				ID:  st.Container.ID(),
+				Err: <-st.Err,
+			}
+			if results[i].Err != nil {
+				failed = ErrTransactionFailed
+				continue
+			}
+			results[i].Container = (<-st.StartResponse).Container
+		}
+		if failed != nil {
+			for _, r := range results {
+				if r.Err == nil {
+					rollbackStart(r.Container)
+				}
+			}
+		}
+		e.TransactionResults = results
+		e.Err <- failed
+		ContainerCreateTimer.UpdateSince(start)
+	}()
+	return errDeferedResponse
+}
+
+// rollbackStart kills the init process of the container, the container is then removed
+// when its exit is handled
+func rollbackStart(c runtime.Container) {
+	if err := killContainer(c); err != nil {
+		containerLog(c.ID()).WithField("error", err).Error("containerd: rollback container start")
+	}
+}
@@ -1,11 +1,23 @@
 package supervisor
 
 import (
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/Sirupsen/logrus"
 	"github.com/docker/containerd/runtime"
 )
 
+// WithPauseTimeout sets how long pausing or resuming a container may take before it
+// is aborted.  A paused container is thawed when its pause times out.  Zero waits
+// forever.
+func WithPauseTimeout(d time.Duration) Option {
+	return func(s *Supervisor) {
+		s.pauseTimeout = d
+	}
+}
+
 type UpdateTask struct {
 	s *Supervisor
 }
@@ -19,53 +31,90 @@ func (h *UpdateTask) Handle(e *Task) error {
 	if e.State != "" {
 		switch e.State {
 		case runtime.Running:
-			if err := container.Resume(); err != nil {
-				return ErrUnknownContainerStatus
+			if err := h.s.resumeContainer(container, e.CorrelationID); err != nil {
+				return err
 			}
-			h.s.notifySubscribers(Event{
-				ID:        e.ID,
-				Type:      "resume",
-				Timestamp: time.Now(),
-			})
 		case runtime.Paused:
-			if err := container.Pause(); err != nil {
-				return ErrUnknownContainerStatus
+			if err := h.s.pauseContainer(container, e.CorrelationID); err != nil {
+				return err
 			}
-			h.s.notifySubscribers(Event{
-				ID:        e.ID,
-				Type:      "pause",
-				Timestamp: time.Now(),
-			})
 		default:
 			return ErrUnknownContainerStatus
 		}
 	}
+	if e.Resources != nil && *e.Resources != (runtime.Resources{}) {
+		old, err := container.Resources()
+		if err != nil {
+			return err
+		}
+		if err := container.UpdateResources(*e.Resources); err != nil {
+			return err
+		}
+		metadata := resourcesMetadata(*e.Resources, old)
+		if limit := e.Resources.PidsLimit; limit > 0 {
+			// the kernel allows a limit below the current number of tasks but
+			// no new tasks can be created until enough have exited
+			if current, err := container.PidsCurrent(); err == nil {
+				metadata["pidsCurrent"] = strconv.FormatInt(current, 10)
+				if current > limit {
+					metadata["pidsLimitExceeded"] = "true"
+					containerLog(e.ID).WithFields(logrus.Fields{
+						"limit":   limit,
+						"current": current,
+					}).Warn("containerd: pids limit is below the current number of tasks")
+				}
+			}
+		}
+		h.s.notifySubscribers(withCorrelationID(Event{
+			ID:        e.ID,
+			Type:      "update",
+			Timestamp: time.Now(),
+			Metadata:  metadata,
+		}, e.CorrelationID))
+	}
 	return nil
 }
 
+// resourcesMetadata returns the changed resource limits as event metadata with their
+// old values under the "Old" suffix, "changed" lists the names of the limits.  Old
+// values that could not be read are left out.
+func resourcesMetadata(r, old runtime.Resources) map[string]string {
+	var (
+		m       = make(map[string]string)
+		changed []string
+	)
+	for _, l := range []struct {
+		name       string
+		value, old int64
+	}{
+		{"cpuShares", r.CpuShares, old.CpuShares},
+		{"cpuQuota", r.CpuQuota, old.CpuQuota},
+		{"cpuPeriod", r.CpuPeriod, old.CpuPeriod},
+		{"memoryLimit", r.MemoryLimit, old.MemoryLimit},
+		{"pidsLimit", r.PidsLimit, old.PidsLimit},
+	} {
+		if l.value == 0 {
+			continue
+		}
+		changed = append(changed, l.name)
+		m[l.name] = strconv.FormatInt(l.value, 10)
+		if l.old != 0 {
+			m[l.name+"Old"] = strconv.FormatInt(l.old, 10)
+		}
+	}
+	m["changed"] = strings.Join(changed, ",")
+	return m
+}
+
 type UpdateProcessTask struct {
 	s *Supervisor
 }
 
 func (h *UpdateProcessTask) Handle(e *Task) error {
-	i, ok := h.s.containers[e.ID]
-	if !ok {
-		return ErrContainerNotFound
-	}
-	processes, err := i.container.Processes()
+	process, err := h.s.findProcess(e.ID, e.Pid)
 	if err != nil {
 		return err
 	}
-	var process runtime.Process
-	for _, p := range processes {
-		if p.ID() == e.Pid {
-			process = p
-			break
-		}
-	}
-	if process == nil {
-		return ErrProcessNotFound
-	}
 	if e.CloseStdin {
 		if err := process.CloseStdin(); err != nil {
 			return err
@@ -78,3 +127,39 @@ func (h *UpdateProcessTask) Handle(e *Task) error {
 	}
 	return nil
 }
+
+type ResizePtyTask struct {
+	s *Supervisor
+}
+
+// Handle sets the window size of the terminal of the process e.Pid in the container
+// e.ID to e.Width and e.Height.  It fails with runtime.ErrTerminalsNotSupported if the
+// process was not started with a terminal.
+func (h *ResizePtyTask) Handle(e *Task) error {
+	if e.Width <= 0 || e.Height <= 0 {
+		return ErrInvalidWindowSize
+	}
+	process, err := h.s.findProcess(e.ID, e.Pid)
+	if err != nil {
+		return err
+	}
+	return process.Resize(e.Width, e.Height)
+}
+
+// findProcess returns the process with the id pid in the container id
+func (s *Supervisor) findProcess(id, pid string) (runtime.Process, error) {
+	i, ok := s.containers[id]
+	if !ok {
+		return nil, ErrContainerNotFound
+	}
+	processes, err := i.container.Processes()
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range processes {
+		if p.ID() == pid {
+			return p, nil
+		}
+	}
+	return nil, ErrProcessNotFound
+}
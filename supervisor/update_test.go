@@ -0,0 +1,28 @@
+package supervisor
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/docker/containerd/runtime"
+)
+
+func TestResourcesMetadataOldValues(t *testing.T) {
+	m := resourcesMetadata(runtime.Resources{
+		CpuShares:   512,
+		MemoryLimit: -1,
+	}, runtime.Resources{
+		CpuShares:   1024,
+		CpuPeriod:   100000,
+		MemoryLimit: 0,
+	})
+	expected := map[string]string{
+		"changed":      "cpuShares,memoryLimit",
+		"cpuShares":    "512",
+		"cpuSharesOld": "1024",
+		"memoryLimit":  "-1",
+	}
+	if !reflect.DeepEqual(m, expected) {
+		t.Fatalf("expected %v but received %v", expected, m)
+	}
+}
@@ -0,0 +1,40 @@
+package supervisor
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Uptime is the supervisor's lifetime and the container activity since it started
+type Uptime struct {
+	StartTime time.Time
+	Uptime    time.Duration
+	// ContainerStarts and ContainerExits are the number of containers started and
+	// exited since the supervisor started
+	ContainerStarts int64
+	ContainerExits  int64
+}
+
+// Uptime returns the time the supervisor was started and how long it has been running
+func (s *Supervisor) Uptime() Uptime {
+	return Uptime{
+		StartTime:       s.startTime,
+		Uptime:          time.Since(s.startTime),
+		ContainerStarts: atomic.LoadInt64(&s.containerStarts),
+		ContainerExits:  atomic.LoadInt64(&s.containerExits),
+	}
+}
+
+// Healthy returns an error if the supervisor is no longer able to detect the exits
+// of container processes
+func (s *Supervisor) Healthy() error {
+	if !s.monitor.Health().Running {
+		return ErrMonitorStopped
+	}
+	return nil
+}
+
+// MonitorHealth returns the state of the supervisor's exit detection
+func (s *Supervisor) MonitorHealth() MonitorHealth {
+	return s.monitor.Health()
+}
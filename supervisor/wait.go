@@ -0,0 +1,57 @@
+package supervisor
+
+import (
+	"sort"
+	"strings"
+
+	"golang.org/x/net/context"
+)
+
+// WaitError is returned by Wait when the context is done before all containers exited
+type WaitError struct {
+	// Running are the ids of the containers that had not exited
+	Running []string
+	Err     error
+}
+
+func (e *WaitError) Error() string {
+	return "containerd: containers still running: " + strings.Join(e.Running, ", ") + ": " + e.Err.Error()
+}
+
+// untrackContainer records that the container was removed from the supervisor and
+// wakes up the callers of Wait
+func (s *Supervisor) untrackContainer(id string) {
+	s.trackLock.Lock()
+	delete(s.tracked, id)
+	close(s.trackChanged)
+	s.trackChanged = make(chan struct{})
+	s.trackLock.Unlock()
+}
+
+// Wait blocks until the exits of all containers have been handled and they have been
+// removed from the supervisor or the context is done, in which case a WaitError with
+// the containers still running is returned.  It is used after Stop to wait for the
+// containers that are stopped on shutdown.
+func (s *Supervisor) Wait(ctx context.Context) error {
+	for {
+		s.trackLock.Lock()
+		var running []string
+		for id := range s.tracked {
+			running = append(running, id)
+		}
+		changed := s.trackChanged
+		s.trackLock.Unlock()
+		if len(running) == 0 {
+			return nil
+		}
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			sort.Strings(running)
+			return &WaitError{
+				Running: running,
+				Err:     ctx.Err(),
+			}
+		}
+	}
+}
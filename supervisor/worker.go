@@ -1,7 +1,9 @@
 package supervisor
 
 import (
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/Sirupsen/logrus"
@@ -14,10 +16,12 @@ type Worker interface {
 
 type startTask struct {
 	Container     runtime.Container
-	Checkpoint    string
+	Checkpoint    *runtime.Checkpoint
 	Stdin         string
 	Stdout        string
 	Stderr        string
+	StatsInterval time.Duration
+	CorrelationID string
 	Err           chan error
 	StartResponse chan StartResponse
 }
@@ -42,7 +46,9 @@ func (w *worker) Start() {
 		if err != nil {
 			evt := NewTask(DeleteTaskType)
 			evt.ID = t.Container.ID()
+			evt.CorrelationID = t.CorrelationID
 			w.s.SendTask(evt)
+			w.s.startFailed(t.Container.ID(), t.CorrelationID, err)
 			t.Err <- err
 			continue
 		}
@@ -59,15 +65,30 @@ func (w *worker) Start() {
 		if err := w.s.monitorProcess(process); err != nil {
 			logrus.WithField("error", err).Error("containerd: add process to monitor")
 		}
+		w.s.collector.add(t.Container, t.StatsInterval)
+		w.s.watchMemoryPressure(t.Container)
+		w.s.updateSnapshot(t.Container, runtime.Running)
+		atomic.AddInt64(&w.s.containerStarts, 1)
 		ContainerStartTimer.UpdateSince(started)
 		t.Err <- nil
 		t.StartResponse <- StartResponse{
 			Container: t.Container,
+			Process:   process,
 		}
-		w.s.notifySubscribers(Event{
+		metadata := map[string]string{
+			systemPidKey: strconv.Itoa(process.SystemPid()),
+		}
+		if t.Checkpoint != nil {
+			// the container was restored from the checkpoint instead of
+			// starting a new init process
+			metadata["checkpoint"] = t.Checkpoint.Name
+			metadata["restored"] = "true"
+		}
+		w.s.notifySubscribers(withCorrelationID(Event{
 			Timestamp: time.Now(),
 			ID:        t.Container.ID(),
 			Type:      "start-container",
-		})
+			Metadata:  metadata,
+		}, t.CorrelationID))
 	}
 }